@@ -24,7 +24,11 @@ func (o *breakingCmdOptions) AddFlags(fs *pflag.FlagSet) {
 func BreakingCommand(globalOpts *globalOptions) *cobra.Command {
 	cmdOpts := breakingCmdOptions{
 		common: commonCompareOptions{
-			output: outputFormatText,
+			commonOutputOptions: commonOutputOptions{
+				output: outputFormatText,
+				failOn: failOnWarning,
+			},
+			engine: compare.EngineOasdiff,
 		},
 	}
 
@@ -66,6 +70,7 @@ func BreakingRunE(globalOpts *globalOptions, cmdOpts *breakingCmdOptions) cobraF
 		diffOpt := compare.CompareOptions{
 			BreakingOnly:       true,
 			IgnoreDescriptions: cmdOpts.common.ignoreDescriptions,
+			Engine:             cmdOpts.common.engine,
 		}
 		report, err := compareCRDs(log, baseCRDs, revisionCRDs, diffOpt)
 		if err != nil {
@@ -79,8 +84,10 @@ func BreakingRunE(globalOpts *globalOptions, cmdOpts *breakingCmdOptions) cobraF
 			// return nil
 		}
 
-		outputReport(log, report, true, &cmdOpts.common)
+		if err := outputReport(report, true, &cmdOpts.common.commonOutputOptions); err != nil {
+			return err
+		}
 
-		return nil
+		return cmdOpts.common.checkExitCode(report)
 	})
 }
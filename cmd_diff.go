@@ -28,7 +28,11 @@ func (o *diffCmdOptions) AddFlags(fs *pflag.FlagSet) {
 func DiffCommand(globalOpts *globalOptions) *cobra.Command {
 	cmdOpts := diffCmdOptions{
 		common: commonCompareOptions{
-			output: outputFormatText,
+			commonOutputOptions: commonOutputOptions{
+				output: outputFormatText,
+				failOn: failOnNone,
+			},
+			engine: compare.EngineOasdiff,
 		},
 	}
 
@@ -69,6 +73,7 @@ func DiffRunE(globalOpts *globalOptions, cmdOpts *diffCmdOptions) cobraFuncE {
 		log.Debug("Comparing CRDs…")
 		diffOpt := compare.CompareOptions{
 			IgnoreDescriptions: cmdOpts.common.ignoreDescriptions,
+			Engine:             cmdOpts.common.engine,
 		}
 		report, err := compareCRDs(log, baseCRDs, revisionCRDs, diffOpt)
 		if err != nil {
@@ -82,8 +87,10 @@ func DiffRunE(globalOpts *globalOptions, cmdOpts *diffCmdOptions) cobraFuncE {
 			// return nil
 		}
 
-		outputReport(log, report, false, &cmdOpts.common)
+		if err := outputReport(report, false, &cmdOpts.common.commonOutputOptions); err != nil {
+			return err
+		}
 
-		return nil
+		return cmdOpts.common.checkExitCode(report)
 	})
 }
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"go.xrstf.de/crdiff/pkg/compare/report"
+	reportv1 "go.xrstf.de/crdiff/pkg/compare/report/v1"
+)
+
+type renderCmdOptions struct {
+	common       commonOutputOptions
+	breakingOnly bool
+}
+
+func (o *renderCmdOptions) AddFlags(fs *pflag.FlagSet) {
+	o.common.AddFlags(fs)
+	fs.BoolVar(&o.breakingOnly, "breaking", o.breakingOnly, "only render breaking changes")
+}
+
+func RenderCommand(globalOpts *globalOptions) *cobra.Command {
+	cmdOpts := renderCmdOptions{
+		common: commonOutputOptions{
+			output: outputFormatText,
+			failOn: failOnNone,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:          "render REPORT",
+		Short:        "Render a JSON report (use - for stdin) in another output format",
+		RunE:         RenderRunE(globalOpts, &cmdOpts),
+		SilenceUsage: true,
+	}
+
+	cmdOpts.AddFlags(cmd.PersistentFlags())
+
+	cmd.PreRunE = cmdOpts.common.PreRunE
+
+	return cmd
+}
+
+func RenderRunE(globalOpts *globalOptions, cmdOpts *renderCmdOptions) cobraFuncE {
+	return handleErrors(func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return cmd.Help()
+		}
+
+		log.Debug("Loading report…")
+		report, err := loadReport(args[0])
+		if err != nil {
+			return fmt.Errorf("failed loading report: %v", err)
+		}
+
+		if err := outputReport(report, cmdOpts.breakingOnly, &cmdOpts.common); err != nil {
+			return err
+		}
+
+		return cmdOpts.common.checkExitCode(report)
+	})
+}
+
+// loadReport reads a versioned JSON report. For compatibility, reports
+// without an apiVersion are decoded as plain report.Report structs, which
+// is what older crdiff releases produced.
+func loadReport(filename string) (*report.Report, error) {
+	var (
+		content []byte
+		err     error
+	)
+
+	if filename == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var header struct {
+		APIVersion string `json:"apiVersion"`
+	}
+
+	if err := json.Unmarshal(content, &header); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if header.APIVersion != "" {
+		return reportv1.Decode(bytes.NewReader(content))
+	}
+
+	legacy := &report.Report{}
+	if err := json.Unmarshal(content, legacy); err != nil {
+		return nil, fmt.Errorf("invalid report: %w", err)
+	}
+
+	return legacy, nil
+}
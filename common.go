@@ -6,48 +6,122 @@ package main
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
 
 	"github.com/gookit/color"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/tufin/oasdiff/checker"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+	"go.xrstf.de/crdiff/pkg/compare/report"
 )
 
 const (
-	outputFormatText = "text"
-	outputFormatJSON = "json"
+	outputFormatText       = "text"
+	outputFormatJSON       = "json"
+	outputFormatYAML       = "yaml"
+	outputFormatMarkdown   = "markdown"
+	outputFormatSARIF      = "sarif"
+	outputFormatGitHub     = "github-actions"
+	outputFormatGitLab     = "gitlab-codequality"
+	outputFormatJUnit      = "junit"
+	outputFormatCheckstyle = "checkstyle"
+	outputFormatHTML       = "html"
+	outputFormatTemplate   = "template"
 )
 
-type commonCompareOptions struct {
-	forceColor         bool
-	noColor            bool
-	output             string
-	ignoreDescriptions bool
+const (
+	failOnNone    = "none"
+	failOnWarning = "warning"
+	failOnError   = "error"
+)
+
+// These are the documented exit codes of crdiff.
+const (
+	exitCodeOK       = 0
+	exitCodeError    = 1
+	exitCodeBreaking = 2
+	exitCodeWarnings = 3
+)
+
+// exitError is returned by commands that completed successfully, but
+// whose result should still make crdiff exit with a non-zero code.
+type exitError struct {
+	code int
 }
 
-func (o *commonCompareOptions) PreRunE(cmd *cobra.Command, args []string) error {
-	// We silence errors so we can have full control over their handling,
-	// but this means nobody is processing the error returned from this
-	// function; so instead we print it ourselves and just return it to
-	// make cobra exit with 1.
-	fail := func(err error) error {
-		log.Errorf("Invalid flags: %v.", err)
-		return err
-	}
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit code %d", e.code)
+}
+
+// failFlags logs an invalid flag error. We silence errors so we can have
+// full control over their handling, but this means nobody is processing
+// the error returned from PreRunE functions; so instead we print it
+// ourselves and just return it to make cobra exit with 1.
+func failFlags(err error) error {
+	log.Errorf("Invalid flags: %v.", err)
+	return err
+}
+
+// commonOutputOptions are shared by all commands that output reports.
+type commonOutputOptions struct {
+	forceColor   bool
+	noColor      bool
+	output       string
+	templateFile string
+	failOn       string
 
+	// template is parsed from templateFile during PreRunE.
+	template *template.Template
+}
+
+func (o *commonOutputOptions) PreRunE(cmd *cobra.Command, args []string) error {
 	// set the log format on the global log variable
 	switch o.output {
-	case outputFormatText:
+	case outputFormatText, outputFormatYAML, outputFormatMarkdown, outputFormatSARIF, outputFormatGitHub, outputFormatGitLab, outputFormatJUnit, outputFormatCheckstyle, outputFormatHTML:
 		// NOP
+	case outputFormatTemplate:
+		if o.templateFile == "" {
+			return failFlags(errors.New("--template is required when using --output=template"))
+		}
 	case outputFormatJSON:
 		log.SetFormatter(&logrus.JSONFormatter{})
 	default:
-		return fail(fmt.Errorf("unknown output format %q", o.output))
+		return failFlags(fmt.Errorf("unknown output format %q", o.output))
+	}
+
+	if o.templateFile != "" {
+		if o.output != outputFormatTemplate {
+			return failFlags(errors.New("--template can only be used with --output=template"))
+		}
+
+		content, err := os.ReadFile(o.templateFile)
+		if err != nil {
+			return failFlags(fmt.Errorf("failed to read template: %w", err))
+		}
+
+		o.template, err = report.NewTemplate(filepath.Base(o.templateFile), string(content))
+		if err != nil {
+			return failFlags(fmt.Errorf("invalid template: %w", err))
+		}
+	}
+
+	switch o.failOn {
+	case failOnNone, failOnWarning, failOnError:
+		// NOP
+	default:
+		return failFlags(fmt.Errorf("unknown --fail-on level %q", o.failOn))
 	}
 
 	// configure gookit
 	if o.forceColor && o.noColor {
-		return fail(errors.New("cannot combine --no-color with --color"))
+		return failFlags(errors.New("cannot combine --no-color with --color"))
 	}
 
 	if o.forceColor {
@@ -59,9 +133,80 @@ func (o *commonCompareOptions) PreRunE(cmd *cobra.Command, args []string) error
 	return nil
 }
 
-func (o *commonCompareOptions) AddFlags(fs *pflag.FlagSet) {
+func (o *commonOutputOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&o.forceColor, "color", o.forceColor, "enable colored output (can also use $FORCE_COLOR)")
 	fs.BoolVar(&o.noColor, "no-color", o.noColor, "disable colored output (can also use $NO_COLOR)")
+	fs.StringVarP(&o.output, "output", "o", o.output, "output format (one of [text, json, yaml, markdown, sarif, github-actions, gitlab-codequality, junit, checkstyle, html, template])")
+	fs.StringVar(&o.templateFile, "template", o.templateFile, "Go template file to render the report with (requires --output=template)")
+	fs.StringVar(&o.failOn, "fail-on", o.failOn, "exit with a non-zero code if breaking changes of at least this level are found (one of [none, warning, error])")
+}
+
+// commonCompareOptions are shared by all commands that compare CRDs.
+type commonCompareOptions struct {
+	commonOutputOptions
+
+	ignoreDescriptions bool
+	engine             string
+}
+
+func (o *commonCompareOptions) PreRunE(cmd *cobra.Command, args []string) error {
+	if err := o.commonOutputOptions.PreRunE(cmd, args); err != nil {
+		return err
+	}
+
+	if !slices.Contains(compare.Engines, o.engine) {
+		return failFlags(fmt.Errorf("unknown engine %q", o.engine))
+	}
+
+	return nil
+}
+
+func (o *commonCompareOptions) AddFlags(fs *pflag.FlagSet) {
+	o.commonOutputOptions.AddFlags(fs)
 	fs.BoolVar(&o.ignoreDescriptions, "ignore-descriptions", o.ignoreDescriptions, "ignore changes to field descriptions")
-	fs.StringVarP(&o.output, "output", "o", o.output, "output format (one of [text, json])")
+	fs.StringVar(&o.engine, "engine", o.engine, fmt.Sprintf("schema diffing engine to use (one of [%s])", strings.Join(compare.Engines, ", ")))
+}
+
+// failOnLevel returns the minimum level that makes crdiff fail,
+// or 0 if it should never fail because of breaking changes.
+func (o *commonOutputOptions) failOnLevel() checker.Level {
+	switch o.failOn {
+	case failOnWarning:
+		return checker.WARN
+	case failOnError:
+		return checker.ERR
+	default:
+		return 0
+	}
+}
+
+// exitCode determines the exit code based on the report and the
+// --fail-on flag: 2 if errors were found, 3 if only warnings were
+// found, 0 otherwise.
+func (o *commonOutputOptions) exitCode(r *report.Report) int {
+	threshold := o.failOnLevel()
+	if threshold == 0 {
+		return exitCodeOK
+	}
+
+	level := r.HighestLevel()
+	if level == 0 || level < threshold {
+		return exitCodeOK
+	}
+
+	if level >= checker.ERR {
+		return exitCodeBreaking
+	}
+
+	return exitCodeWarnings
+}
+
+// checkExitCode returns an error that makes crdiff exit with the
+// appropriate code, or nil if the report does not violate the policy.
+func (o *commonOutputOptions) checkExitCode(r *report.Report) error {
+	if code := o.exitCode(r); code != exitCodeOK {
+		return &exitError{code: code}
+	}
+
+	return nil
 }
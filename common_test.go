@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tufin/oasdiff/checker"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+	"go.xrstf.de/crdiff/pkg/compare/report"
+)
+
+func reportWithLevel(level checker.Level) *report.Report {
+	r := &report.Report{
+		Diffs: map[string]compare.CRDDiff{},
+	}
+
+	if level > 0 {
+		r.Diffs["example.com/Thing"] = compare.CRDDiff{
+			General: []compare.Change{{
+				Breaking:    true,
+				Level:       level,
+				Description: "something changed",
+			}},
+		}
+	}
+
+	return r
+}
+
+func TestExitCode(t *testing.T) {
+	testcases := []struct {
+		failOn   string
+		level    checker.Level
+		expected int
+	}{
+		{failOn: failOnNone, level: 0, expected: exitCodeOK},
+		{failOn: failOnNone, level: checker.INFO, expected: exitCodeOK},
+		{failOn: failOnNone, level: checker.WARN, expected: exitCodeOK},
+		{failOn: failOnNone, level: checker.ERR, expected: exitCodeOK},
+
+		{failOn: failOnWarning, level: 0, expected: exitCodeOK},
+		{failOn: failOnWarning, level: checker.INFO, expected: exitCodeOK},
+		{failOn: failOnWarning, level: checker.WARN, expected: exitCodeWarnings},
+		{failOn: failOnWarning, level: checker.ERR, expected: exitCodeBreaking},
+
+		{failOn: failOnError, level: 0, expected: exitCodeOK},
+		{failOn: failOnError, level: checker.INFO, expected: exitCodeOK},
+		{failOn: failOnError, level: checker.WARN, expected: exitCodeOK},
+		{failOn: failOnError, level: checker.ERR, expected: exitCodeBreaking},
+	}
+
+	for _, tc := range testcases {
+		t.Run(fmt.Sprintf("%s/%d", tc.failOn, tc.level), func(t *testing.T) {
+			opts := &commonOutputOptions{failOn: tc.failOn}
+			r := reportWithLevel(tc.level)
+
+			if level := r.HighestLevel(); level != tc.level {
+				t.Fatalf("Test report should have level %v, but has %v.", tc.level, level)
+			}
+
+			if code := opts.exitCode(r); code != tc.expected {
+				t.Errorf("Expected exit code %d, got %d.", tc.expected, code)
+			}
+
+			err := opts.checkExitCode(r)
+			if tc.expected == exitCodeOK {
+				if err != nil {
+					t.Errorf("Expected no error, got %v.", err)
+				}
+			} else if exitErr, ok := err.(*exitError); !ok || exitErr.code != tc.expected {
+				t.Errorf("Expected exitError with code %d, got %v.", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestExitCodeWithoutLevel(t *testing.T) {
+	r := &report.Report{
+		Diffs: map[string]compare.CRDDiff{
+			"example.com/Thing": {
+				ChangedVersions: map[string]compare.CRDVersionDiff{
+					"v1": {
+						General: []compare.Change{{Breaking: true, Description: "something changed"}},
+					},
+				},
+			},
+		},
+	}
+
+	if level := r.HighestLevel(); level != checker.ERR {
+		t.Errorf("Expected breaking change without level to be an error, but got level %v.", level)
+	}
+
+	for _, failOn := range []string{failOnWarning, failOnError} {
+		opts := &commonOutputOptions{failOn: failOn}
+		if code := opts.exitCode(r); code != exitCodeBreaking {
+			t.Errorf("Expected exit code %d with --fail-on=%s, got %d.", exitCodeBreaking, failOn, code)
+		}
+	}
+}
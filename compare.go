@@ -4,6 +4,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,23 +13,22 @@ import (
 
 	"go.xrstf.de/crdiff/pkg/compare"
 	"go.xrstf.de/crdiff/pkg/compare/report"
+	reportv1 "go.xrstf.de/crdiff/pkg/compare/report/v1"
 	"go.xrstf.de/crdiff/pkg/crd"
 )
 
 func compareCRDs(log logrus.FieldLogger, baseCRDs, revisionCRDs map[string]crd.CRD, diffOpt compare.CompareOptions) (*report.Report, error) {
-	report := &report.Report{
-		Diffs: map[string]compare.CRDDiff{},
+	result := &report.Report{
+		Diffs:     map[string]compare.CRDDiff{},
+		AddedCRDs: map[string]report.AddedCRD{},
+		Sources:   map[string]*crd.Source{},
 	}
 
 	for crdIdentifier, baseCRD := range baseCRDs {
 		revisionCRD, exists := revisionCRDs[crdIdentifier]
 		if !exists {
-			report.Diffs[crdIdentifier] = compare.CRDDiff{
-				General: []compare.Change{{
-					Breaking:    true,
-					Description: "CRD has been removed",
-				}},
-			}
+			result.Diffs[crdIdentifier] = compare.RemovedCRDDiff()
+			result.Sources[crdIdentifier] = baseCRD.Source()
 
 			continue
 		}
@@ -39,22 +39,84 @@ func compareCRDs(log logrus.FieldLogger, baseCRDs, revisionCRDs map[string]crd.C
 		}
 
 		if crdChanges.HasChanges() {
-			report.Diffs[crdIdentifier] = *crdChanges
+			result.Diffs[crdIdentifier] = *crdChanges
+			result.Sources[crdIdentifier] = revisionCRD.Source()
+		}
+	}
+
+	// adding new CRDs is never breaking
+	if !diffOpt.BreakingOnly {
+		for crdIdentifier, revisionCRD := range revisionCRDs {
+			if _, exists := baseCRDs[crdIdentifier]; exists {
+				continue
+			}
+
+			versions, err := revisionCRD.Versions()
+			if err != nil {
+				return nil, fmt.Errorf("failed to determine versions of %q: %w", crdIdentifier, err)
+			}
+
+			result.AddedCRDs[crdIdentifier] = report.AddedCRD{
+				Scope:    revisionCRD.Scope(),
+				Versions: versions,
+				Names:    revisionCRD.Names(),
+			}
+			result.Sources[crdIdentifier] = revisionCRD.Source()
 		}
 	}
 
-	return report, nil
+	return result, nil
 }
 
-func outputReport(log logrus.FieldLogger, report *report.Report, breakingOnly bool, opts *commonCompareOptions) {
+func outputReport(report *report.Report, breakingOnly bool, opts *commonOutputOptions) error {
+	var (
+		output []byte
+		err    error
+	)
+
+	// the machine-readable report formats have no notion of breakingOnly
+	if breakingOnly && (opts.output == outputFormatJSON || opts.output == outputFormatYAML) {
+		report = report.OnlyBreaking()
+	}
+
 	switch opts.output {
 	case outputFormatText:
 		report.Print(breakingOnly)
+		return nil
 	case outputFormatJSON:
-		if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
-			log.Errorf("Failed to render output as JSON: %v", err)
-		}
+		output, err = json.Marshal(reportv1.FromReport(report))
+		output = append(output, '\n')
+	case outputFormatYAML:
+		var buf bytes.Buffer
+		err = reportv1.EncodeYAML(&buf, reportv1.FromReport(report))
+		output = buf.Bytes()
+	case outputFormatMarkdown:
+		output = []byte(report.RenderMarkdown(breakingOnly))
+	case outputFormatSARIF:
+		output, err = report.RenderSARIF(BuildTag)
+	case outputFormatGitHub:
+		output = []byte(report.RenderGitHubActions())
+	case outputFormatGitLab:
+		output, err = report.RenderGitLabCodeQuality()
+	case outputFormatJUnit:
+		output, err = report.RenderJUnit()
+	case outputFormatCheckstyle:
+		output, err = report.RenderCheckstyle()
+	case outputFormatHTML:
+		output, err = report.RenderHTML(breakingOnly)
+	case outputFormatTemplate:
+		output, err = report.RenderTemplate(opts.template, breakingOnly)
 	default:
-		log.Errorf("This should never happen: Do not know how to handle %s output format.", opts.output)
+		return fmt.Errorf("this should never happen: do not know how to handle %s output format", opts.output)
 	}
+
+	if err != nil {
+		return fmt.Errorf("failed to render output as %s: %w", opts.output, err)
+	}
+
+	if _, err := os.Stdout.Write(output); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+	"go.xrstf.de/crdiff/pkg/compare/report"
+	"go.xrstf.de/crdiff/pkg/crd"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+func testCRD(kind string, versions ...string) crd.CRD {
+	c := apiextensionsv1.CustomResourceDefinition{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Scope: apiextensionsv1.NamespaceScoped,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:     kind,
+				Plural:   "things",
+				ListKind: kind + "List",
+			},
+		},
+	}
+
+	for _, version := range versions {
+		c.Spec.Versions = append(c.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{
+			Name:    version,
+			Served:  true,
+			Storage: version == versions[0],
+		})
+	}
+
+	return crd.NewV1(c)
+}
+
+func TestCompareCRDsAddedCRD(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	existing := testCRD("Existing", "v1")
+	added := testCRD("Thing", "v1", "v1alpha1")
+
+	baseCRDs := map[string]crd.CRD{existing.Identifier(): existing}
+	revisionCRDs := map[string]crd.CRD{existing.Identifier(): existing, added.Identifier(): added}
+
+	result, err := compareCRDs(log, baseCRDs, revisionCRDs, compare.CompareOptions{})
+	if err != nil {
+		t.Fatalf("Failed to compare CRDs: %v", err)
+	}
+
+	expected := map[string]report.AddedCRD{
+		"example.com/Thing": {
+			Scope:    "Namespaced",
+			Versions: []string{"v1", "v1alpha1"},
+			Names:    added.Names(),
+		},
+	}
+
+	if !equality.Semantic.DeepEqual(expected, result.AddedCRDs) {
+		t.Fatalf("Expected added CRDs %+v, but got %+v", expected, result.AddedCRDs)
+	}
+
+	if len(result.Diffs) > 0 {
+		t.Errorf("Expected no diffs for unchanged CRDs, but got %+v", result.Diffs)
+	}
+
+	if !result.HasChanges() {
+		t.Error("Expected report with an added CRD to have changes.")
+	}
+
+	if level := result.HighestLevel(); level != 0 {
+		t.Errorf("Expected adding a CRD to not be breaking, but got level %v", level)
+	}
+
+	// adding CRDs is never breaking
+	result, err = compareCRDs(log, baseCRDs, revisionCRDs, compare.CompareOptions{BreakingOnly: true})
+	if err != nil {
+		t.Fatalf("Failed to compare CRDs: %v", err)
+	}
+
+	if len(result.AddedCRDs) > 0 {
+		t.Errorf("Expected no added CRDs when only breaking changes are requested, but got %+v", result.AddedCRDs)
+	}
+}
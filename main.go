@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
@@ -64,6 +65,7 @@ func main() {
 	rootCmd.AddCommand(
 		DiffCommand(&opts),
 		BreakingCommand(&opts),
+		RenderCommand(&opts),
 		VersionCommand(&opts),
 	)
 
@@ -71,7 +73,12 @@ func main() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	if err := rootCmd.ExecuteContext(ctx); err != nil {
-		os.Exit(1)
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+
+		os.Exit(exitCodeError)
 	}
 }
 
@@ -79,7 +86,11 @@ func handleErrors(action cobraFuncE) cobraFuncE {
 	return func(cmd *cobra.Command, args []string) error {
 		err := action(cmd, args)
 		if err != nil {
-			log.Errorf("Operation failed: %v.", err)
+			// exit codes are not errors that need to be logged
+			var exitErr *exitError
+			if !errors.As(err, &exitErr) {
+				log.Errorf("Operation failed: %v.", err)
+			}
 		}
 
 		return err
@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tufin/oasdiff/checker"
+
+	"go.xrstf.de/crdiff/pkg/crd"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// compareConversion compares the spec.conversion of both CRDs and
+// additionally warns if the revision starts to serve multiple versions with
+// different schemas without having a conversion webhook.
+func compareConversion(base, revision crd.CRD, baseVersions, revisionVersions []string) []Change {
+	changes := []Change{}
+
+	baseConversion := base.Conversion()
+	revisionConversion := revision.Conversion()
+
+	baseStrategy := conversionStrategy(baseConversion)
+	revisionStrategy := conversionStrategy(revisionConversion)
+
+	if baseStrategy != revisionStrategy {
+		changes = append(changes, Change{
+			Breaking:    true,
+			Level:       checker.WARN,
+			Description: fmt.Sprintf("changed conversion strategy from %s to %s", baseStrategy, revisionStrategy),
+		})
+	}
+
+	if baseStrategy == apiextensionsv1.WebhookConverter && revisionStrategy == apiextensionsv1.WebhookConverter {
+		baseWebhook := baseConversion.Webhook
+		if baseWebhook == nil {
+			baseWebhook = &apiextensionsv1.WebhookConversion{}
+		}
+
+		revisionWebhook := revisionConversion.Webhook
+		if revisionWebhook == nil {
+			revisionWebhook = &apiextensionsv1.WebhookConversion{}
+		}
+
+		changes = append(changes, compareConversionReviewVersions(baseWebhook.ConversionReviewVersions, revisionWebhook.ConversionReviewVersions)...)
+
+		baseClient := describeWebhookClientConfig(baseWebhook.ClientConfig)
+		revisionClient := describeWebhookClientConfig(revisionWebhook.ClientConfig)
+
+		if baseClient != revisionClient {
+			changes = append(changes, Change{
+				Breaking:    true,
+				Level:       checker.WARN,
+				Description: fmt.Sprintf("changed conversion webhook from %s to %s", baseClient, revisionClient),
+			})
+		}
+	}
+
+	// only warn if the situation is new, not if the base already had the same problem
+	if revisionStrategy == apiextensionsv1.NoneConverter && !lacksConversion(base, baseStrategy, baseVersions) {
+		if served := servedVersionsWithDifferentSchemas(revision, revisionVersions); len(served) > 0 {
+			changes = append(changes, Change{
+				Breaking:    true,
+				Level:       checker.WARN,
+				Description: fmt.Sprintf("versions %s are served with different schemas, but no conversion webhook is configured", strings.Join(served, ", ")),
+			})
+		}
+	}
+
+	return changes
+}
+
+func conversionStrategy(conversion *apiextensionsv1.CustomResourceConversion) apiextensionsv1.ConversionStrategyType {
+	if conversion == nil || conversion.Strategy == "" {
+		return apiextensionsv1.NoneConverter
+	}
+
+	return conversion.Strategy
+}
+
+// compareConversionReviewVersions compares the ConversionReview versions a
+// webhook understands. The apiserver uses the first version it supports, so
+// dropping any of them can make the webhook unusable.
+func compareConversionReviewVersions(base, revision []string) []Change {
+	changes := []Change{}
+
+	baseSet := sets.New(base...)
+	revisionSet := sets.New(revision...)
+
+	if removed := baseSet.Difference(revisionSet); removed.Len() > 0 {
+		changes = append(changes, Change{
+			Breaking:    true,
+			Level:       checker.ERR,
+			Description: fmt.Sprintf("removed conversion review versions %s", strings.Join(sets.List(removed), ", ")),
+		})
+	}
+
+	if added := revisionSet.Difference(baseSet); added.Len() > 0 {
+		changes = append(changes, Change{
+			Description: fmt.Sprintf("added conversion review versions %s", strings.Join(sets.List(added), ", ")),
+		})
+	}
+
+	return changes
+}
+
+// describeWebhookClientConfig turns the client config into a human readable
+// string that can be used both for comparing and reporting. The CA bundle is
+// ignored, as it is commonly injected at runtime.
+func describeWebhookClientConfig(cc *apiextensionsv1.WebhookClientConfig) string {
+	if cc == nil {
+		return "<none>"
+	}
+
+	if cc.URL != nil {
+		return *cc.URL
+	}
+
+	if svc := cc.Service; svc != nil {
+		port := int32(443)
+		if svc.Port != nil {
+			port = *svc.Port
+		}
+
+		return fmt.Sprintf("service %s/%s:%d%s", svc.Namespace, svc.Name, port, stringValue(svc.Path))
+	}
+
+	return "<none>"
+}
+
+// lacksConversion returns true if the CRD serves multiple versions with
+// different schemas without a conversion webhook.
+func lacksConversion(c crd.CRD, strategy apiextensionsv1.ConversionStrategyType, versions []string) bool {
+	return strategy == apiextensionsv1.NoneConverter && len(servedVersionsWithDifferentSchemas(c, versions)) > 0
+}
+
+// servedVersionsWithDifferentSchemas returns all served versions, if at least
+// two of them have different schemas.
+func servedVersionsWithDifferentSchemas(c crd.CRD, versions []string) []string {
+	served := []string{}
+
+	for _, version := range versions {
+		if v := c.Version(version); v != nil && v.Served {
+			served = append(served, version)
+		}
+	}
+
+	for i := 1; i < len(served); i++ {
+		if !equality.Semantic.DeepEqual(c.Schema(served[0]), c.Schema(served[i])) {
+			return served
+		}
+	}
+
+	return nil
+}
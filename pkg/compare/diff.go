@@ -4,8 +4,8 @@
 package compare
 
 import (
-	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/tufin/oasdiff/checker"
 	"github.com/tufin/oasdiff/diff"
@@ -14,6 +14,7 @@ import (
 	"go.xrstf.de/crdiff/pkg/compare/oasdiff"
 	"go.xrstf.de/crdiff/pkg/crd"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -21,6 +22,10 @@ type CompareOptions struct {
 	Versions           []string
 	BreakingOnly       bool
 	IgnoreDescriptions bool
+
+	// Engine is the schema diffing engine to use, one of Engines.
+	// Defaults to EngineOasdiff.
+	Engine string
 }
 
 func CompareCRDs(base, revision crd.CRD, opt CompareOptions) (*CRDDiff, error) {
@@ -28,6 +33,10 @@ func CompareCRDs(base, revision crd.CRD, opt CompareOptions) (*CRDDiff, error) {
 		return nil, fmt.Errorf("cannot compare to different CRDs (%q vs. %q)", base.Identifier(), revision.Identifier())
 	}
 
+	if opt.Engine != "" && !slices.Contains(Engines, opt.Engine) {
+		return nil, fmt.Errorf("unknown engine %q", opt.Engine)
+	}
+
 	baseVersions, err := base.Versions()
 	if err != nil {
 		return nil, fmt.Errorf("failed to determine versions of base CRD: %w", err)
@@ -52,10 +61,16 @@ func CompareCRDs(base, revision crd.CRD, opt CompareOptions) (*CRDDiff, error) {
 	if base.Scope() != revision.Scope() {
 		result.General = append(result.General, Change{
 			Breaking:    true,
+			Level:       checker.ERR,
 			Description: fmt.Sprintf("changed scope from %q to %q", base.Scope(), revision.Scope()),
 		})
 	}
 
+	result.General = append(result.General, compareNames(base.Names(), revision.Names())...)
+	result.General = append(result.General, comparePreserveUnknownFields(base.PreserveUnknownFields(), revision.PreserveUnknownFields())...)
+	result.General = append(result.General, compareStorageVersions(base, revision, baseVersions, revisionVersions)...)
+	result.General = append(result.General, compareConversion(base, revision, baseVersions, revisionVersions)...)
+
 	// compare schemas
 
 	oasConfig := oasdiff.NewConfig()
@@ -66,20 +81,51 @@ func CompareCRDs(base, revision crd.CRD, opt CompareOptions) (*CRDDiff, error) {
 			continue
 		}
 
+		versionDiff := CRDVersionDiff{
+			General:         compareVersionMetadata(base.Version(version), revision.Version(version)),
+			SchemaChanges:   map[string]CRDSchemaDiff{},
+			BreakingChanges: []BreakingChange{},
+		}
+
 		baseSchema := base.Schema(version)
 		revisionSchema := revision.Schema(version)
 
-		completeDiff, breakingChanges, err := oasdiff.CompareSchemas(oasConfig, baseSchema, revisionSchema)
-		if err != nil {
-			return nil, fmt.Errorf("failed comparing version %v: %w", version, err)
+		versionDiff.General = append(versionDiff.General, compareSubresources(base.Subresources(version), revision.Subresources(version), baseSchema, revisionSchema)...)
+
+		if baseSchema == nil || revisionSchema == nil {
+			// oasdiff cannot handle missing schemas, so this case is handled separately
+			versionDiff.General = append(versionDiff.General, compareSchemaExistence(baseSchema, revisionSchema)...)
+		} else {
+			completeDiff, breakingChanges, err := compareSchemas(opt.Engine, oasConfig, baseSchema, revisionSchema)
+			if err != nil {
+				return nil, fmt.Errorf("failed comparing version %v: %w", version, err)
+			}
+
+			if completeDiff != nil {
+				versionDiff.BreakingChanges = append(versionDiff.BreakingChanges, breakingChanges...)
+				collectChangesFromSchemaDiff(versionDiff, completeDiff, &opt, "")
+				dropCompatibleTypeChanges(&versionDiff)
+			}
+
+			// oasdiff does not understand Kubernetes extensions
+			compareListTypes(&versionDiff, baseSchema, revisionSchema)
+			compareValidationRules(&versionDiff, baseSchema, revisionSchema)
+			comparePruning(&versionDiff, baseSchema, revisionSchema)
+			compareTypeSemantics(&versionDiff, baseSchema, revisionSchema)
+		}
+
+		versionDiff.PrinterColumns = comparePrinterColumns(base.PrinterColumns(version), revision.PrinterColumns(version), baseSchema, revisionSchema, &opt)
+
+		if opt.BreakingOnly {
+			versionDiff.General = onlyBreaking(versionDiff.General)
 		}
 
 		// no changes in this version :)
-		if completeDiff == nil {
+		if !versionDiff.HasChanges() {
 			continue
 		}
 
-		result.ChangedVersions[version] = createCRDVersionDiff(completeDiff, breakingChanges, &opt)
+		result.ChangedVersions[version] = versionDiff
 	}
 
 	// detect newly added versions in this CRD
@@ -95,37 +141,48 @@ func CompareCRDs(base, revision crd.CRD, opt CompareOptions) (*CRDDiff, error) {
 		}
 	}
 
+	if opt.BreakingOnly {
+		result.General = onlyBreaking(result.General)
+	}
+
 	result.AddedVersions.Sort()
 	result.DeletedVersions.Sort()
 
 	return result, nil
 }
 
-func createCRDVersionDiff(diff *diff.SchemaDiff, breaking checker.Changes, opt *CompareOptions) CRDVersionDiff {
-	result := CRDVersionDiff{
-		SchemaChanges:   map[string]CRDSchemaDiff{},
-		BreakingChanges: make([]BreakingChange, len(breaking)),
+// compareSchemaExistence handles versions without an openAPIV3Schema.
+// Losing the schema means objects are no longer validated, gaining one
+// means previously valid objects might now be rejected.
+func compareSchemaExistence(base, revision *apiextensionsv1.JSONSchemaProps) []Change {
+	switch {
+	case base != nil && revision == nil:
+		return []Change{{
+			Breaking:    true,
+			Level:       checker.WARN,
+			Description: "removed schema; objects are no longer validated",
+		}}
+
+	case base == nil && revision != nil:
+		return []Change{{
+			Breaking:    true,
+			Level:       checker.ERR,
+			Description: "added schema; existing objects might not be valid anymore",
+		}}
 	}
 
-	for i, change := range breaking {
-		msg := oasdiff.LocalizedMessage{}
+	return nil
+}
 
-		// unwrap the localizer data we sneakily injected by using a JSON localizer
-		if text := change.GetText(); text != "" {
-			if err := json.Unmarshal([]byte(text), &msg); err != nil {
-				panic(err)
-			}
-		}
+func onlyBreaking(changes []Change) []Change {
+	result := []Change{}
 
-		result.BreakingChanges[i] = BreakingChange{
-			ID:      change.GetId(),
-			Level:   change.GetLevel(),
-			Details: msg.Disect(),
+	for _, c := range changes {
+		if c.Breaking {
+			result = append(result, c)
 		}
 	}
 
-	collectChangesFromSchemaDiff(result, diff, opt, "")
-
 	return result
 }
 
@@ -211,8 +268,9 @@ func collectChangesFromSchemaDiff(result CRDVersionDiff, sd *diff.SchemaDiff, op
 func collectChangesFromSchemasDiff(result CRDVersionDiff, sd *diff.SchemasDiff, opt *CompareOptions, path string) {
 	if len(sd.Added) > 0 || len(sd.Deleted) > 0 {
 		schemaDiff := result.SchemaChanges[rootPath(path)] // rely on Go's runtime defaulting for non-existing keys
-		schemaDiff.AddedProperties = sd.Added
-		schemaDiff.DeletedProperties = sd.Deleted
+		// oasdiff does not guarantee any order
+		schemaDiff.AddedProperties = sortedStringList(sd.Added)
+		schemaDiff.DeletedProperties = sortedStringList(sd.Deleted)
 
 		result.SchemaChanges[rootPath(path)] = schemaDiff
 	}
@@ -222,6 +280,13 @@ func collectChangesFromSchemasDiff(result CRDVersionDiff, sd *diff.SchemasDiff,
 	}
 }
 
+func sortedStringList(list utils.StringList) utils.StringList {
+	sorted := slices.Clone(list)
+	sorted.Sort()
+
+	return sorted
+}
+
 func limitVersions(allVersions, limited []string) sets.Set[string] {
 	result := sets.New(allVersions...)
 
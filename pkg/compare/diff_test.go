@@ -32,18 +32,74 @@ func TestCompareCRDs(t *testing.T) {
 	for _, baseFile := range testcases {
 		basename := strings.Replace(filepath.Base(baseFile), ".base.yaml", "", -1)
 
-		t.Run(basename, func(t *testing.T) {
-			testCompareSingleCRD(t, baseFile)
+		for _, engine := range Engines {
+			t.Run(fmt.Sprintf("%s/%s", basename, engine), func(t *testing.T) {
+				testCompareSingleCRD(t, baseFile, engine)
+			})
+		}
+	}
+}
+
+func BenchmarkCompareCRDs(b *testing.B) {
+	testcases, err := filepath.Glob("testdata/*.base.yaml")
+	if err != nil {
+		b.Fatalf("Failed to find testcases: %v", err)
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	type pair struct {
+		base     crd.CRD
+		revision crd.CRD
+	}
+
+	pairs := []pair{}
+	for _, baseFile := range testcases {
+		revisionFile := strings.Replace(baseFile, ".base.yaml", ".revision.yaml", -1)
+
+		baseCRD, err := loadCRD(log, baseFile)
+		if err != nil {
+			b.Fatalf("Failed to load base CRD: %v", err)
+		}
+
+		revisionCRD, err := loadCRD(log, revisionFile)
+		if err != nil {
+			b.Fatalf("Failed to load revision CRD: %v", err)
+		}
+
+		pairs = append(pairs, pair{base: baseCRD, revision: revisionCRD})
+	}
+
+	for _, engine := range Engines {
+		b.Run(engine, func(b *testing.B) {
+			opt := CompareOptions{
+				Engine: engine,
+			}
+
+			for i := 0; i < b.N; i++ {
+				for _, p := range pairs {
+					if _, err := CompareCRDs(p.base, p.revision, opt); err != nil {
+						b.Fatalf("Failed to compare CRDs: %v", err)
+					}
+				}
+			}
 		})
 	}
 }
 
-func testCompareSingleCRD(t *testing.T, baseFile string) {
+func testCompareSingleCRD(t *testing.T, baseFile string, engine string) {
 	t.Helper()
 
 	revisionFile := strings.Replace(baseFile, ".base.yaml", ".revision.yaml", -1)
 	diffFile := strings.Replace(baseFile, ".base.yaml", ".diff.yaml", -1)
 
+	// engines can have their own expectations where their results differ
+	engineDiffFile := strings.Replace(baseFile, ".base.yaml", "."+engine+".diff.yaml", -1)
+	if _, err := os.Stat(engineDiffFile); err == nil {
+		diffFile = engineDiffFile
+	}
+
 	log := logrus.New()
 	log.SetOutput(io.Discard)
 
@@ -57,7 +113,9 @@ func testCompareSingleCRD(t *testing.T, baseFile string) {
 		t.Fatalf("Failed to load revision CRD: %v", err)
 	}
 
-	opt := CompareOptions{}
+	opt := CompareOptions{
+		Engine: engine,
+	}
 
 	result, err := CompareCRDs(baseCRD, revisionCRD, opt)
 	if err != nil {
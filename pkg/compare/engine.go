@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tufin/oasdiff/diff"
+
+	"go.xrstf.de/crdiff/pkg/compare/native"
+	"go.xrstf.de/crdiff/pkg/compare/oasdiff"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+const (
+	// EngineOasdiff converts schemas into OpenAPI specs and compares them
+	// using oasdiff. This is the default engine.
+	EngineOasdiff = "oasdiff"
+
+	// EngineNative compares the JSONSchemaProps of both CRDs directly.
+	EngineNative = "native"
+)
+
+// Engines is the list of all supported schema diffing engines.
+var Engines = []string{EngineOasdiff, EngineNative}
+
+// compareSchemas compares two schemas using the given engine.
+func compareSchemas(engine string, oasConfig *diff.Config, base, revision *apiextensionsv1.JSONSchemaProps) (*diff.SchemaDiff, []BreakingChange, error) {
+	base = normalizeSchema(base)
+	revision = normalizeSchema(revision)
+
+	switch engine {
+	case "", EngineOasdiff:
+		schemaDiff, checkerChanges, err := oasdiff.CompareSchemas(oasConfig, base, revision)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		changes := []BreakingChange{}
+		for _, change := range checkerChanges {
+			msg := oasdiff.LocalizedMessage{}
+
+			// unwrap the localizer data we sneakily injected by using a JSON localizer
+			if text := change.GetText(); text != "" {
+				if err := json.Unmarshal([]byte(text), &msg); err != nil {
+					return nil, nil, fmt.Errorf("failed to decode breaking change: %w", err)
+				}
+			}
+
+			details, err := msg.Disect()
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to decode breaking change %s: %w", change.GetId(), err)
+			}
+
+			changes = append(changes, BreakingChange{
+				ID:      change.GetId(),
+				Level:   change.GetLevel(),
+				Details: details,
+			})
+		}
+
+		return schemaDiff, changes, nil
+
+	case EngineNative:
+		schemaDiff, nativeChanges := native.CompareSchemas(base, revision)
+
+		changes := []BreakingChange{}
+		for _, change := range nativeChanges {
+			changes = append(changes, BreakingChange{
+				ID:      change.ID,
+				Level:   change.Level,
+				Details: change.Details,
+			})
+		}
+
+		return schemaDiff, changes, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown engine %q", engine)
+	}
+}
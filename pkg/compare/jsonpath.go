@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// schemaHasPath checks whether the given simple JSONPath (as used by printer
+// columns and the scale subresource, e.g. ".spec.replicas" or
+// ".status.conditions[?(@.type=="Ready")].status") points to a field that
+// can exist according to the schema. Paths into schema-less subtrees (e.g.
+// metadata or fields with x-kubernetes-preserve-unknown-fields) are always
+// considered to be valid.
+func schemaHasPath(schema *apiextensionsv1.JSONSchemaProps, jsonPath string) bool {
+	// without a schema, nothing can be checked
+	if schema == nil {
+		return true
+	}
+
+	steps := splitJSONPath(jsonPath)
+	if len(steps) == 0 {
+		return true
+	}
+
+	// metadata is not part of the CRD schema, but always present
+	if first := steps[0]; !first.items && (first.field == "metadata" || first.field == "apiVersion" || first.field == "kind") {
+		return true
+	}
+
+	node := schema
+
+	for _, step := range steps {
+		if node.XPreserveUnknownFields != nil && *node.XPreserveUnknownFields {
+			return true
+		}
+
+		if step.items {
+			if node.Items == nil || node.Items.Schema == nil {
+				return false
+			}
+
+			node = node.Items.Schema
+			continue
+		}
+
+		if node.XEmbeddedResource && (step.field == "metadata" || step.field == "apiVersion" || step.field == "kind") {
+			return true
+		}
+
+		if prop, exists := node.Properties[step.field]; exists {
+			node = &prop
+			continue
+		}
+
+		if additional := node.AdditionalProperties; additional != nil {
+			if additional.Schema != nil {
+				node = additional.Schema
+				continue
+			}
+
+			return additional.Allows
+		}
+
+		return false
+	}
+
+	return true
+}
+
+type jsonPathStep struct {
+	field string
+	items bool
+}
+
+// splitJSONPath splits a JSONPath into field and array steps. Array index,
+// wildcard and filter expressions are all treated as "some item of the list".
+func splitJSONPath(jsonPath string) []jsonPathStep {
+	jsonPath = strings.TrimSpace(jsonPath)
+	jsonPath = strings.TrimPrefix(jsonPath, "{")
+	jsonPath = strings.TrimSuffix(jsonPath, "}")
+	jsonPath = strings.TrimPrefix(jsonPath, "$")
+
+	steps := []jsonPathStep{}
+	current := strings.Builder{}
+
+	flush := func() {
+		if current.Len() > 0 {
+			steps = append(steps, jsonPathStep{field: current.String()})
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(jsonPath); i++ {
+		switch c := jsonPath[i]; c {
+		case '.':
+			flush()
+
+		case '[':
+			flush()
+
+			// find the matching closing bracket, filter expressions can contain nested brackets
+			depth := 1
+			start := i + 1
+			for i++; i < len(jsonPath) && depth > 0; i++ {
+				switch jsonPath[i] {
+				case '[':
+					depth++
+				case ']':
+					depth--
+				}
+			}
+			i-- // the loop will increment i again
+
+			content := jsonPath[start:i]
+
+			// ['some.field'] is a field accessor, everything else points into a list
+			if len(content) >= 2 && (content[0] == '\'' || content[0] == '"') && content[len(content)-1] == content[0] {
+				steps = append(steps, jsonPathStep{field: content[1 : len(content)-1]})
+			} else {
+				steps = append(steps, jsonPathStep{items: true})
+			}
+
+		default:
+			current.WriteByte(c)
+		}
+	}
+
+	flush()
+
+	return steps
+}
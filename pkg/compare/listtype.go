@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"slices"
+
+	"github.com/tufin/oasdiff/checker"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+const (
+	listTypeAtomic = "atomic"
+	listTypeSet    = "set"
+	listTypeMap    = "map"
+)
+
+// compareListTypes detects changes to x-kubernetes-list-type and
+// x-kubernetes-list-map-keys, which control how lists are merged by
+// server-side apply and how field ownership is tracked.
+func compareListTypes(result *CRDVersionDiff, base, revision *apiextensionsv1.JSONSchemaProps) {
+	walkSchemas(base, revision, "", func(path string, b, r *apiextensionsv1.JSONSchemaProps) {
+		// type changes are already reported by oasdiff
+		if b.Type != "array" || r.Type != "array" {
+			return
+		}
+
+		baseType := listType(b)
+		revisionType := listType(r)
+
+		if baseType != revisionType {
+			updateSchemaDiff(result, path, func(d *CRDSchemaDiff) {
+				d.ListType = &StringChange{From: baseType, To: revisionType}
+			})
+
+			result.BreakingChanges = append(result.BreakingChanges, BreakingChange{
+				ID:    ListTypeChangedID,
+				Level: listTypeChangeLevel(baseType, revisionType),
+				Details: &ListTypeChangedMessage{
+					Path: path,
+					From: baseType,
+					To:   revisionType,
+				},
+			})
+		}
+
+		if !slices.Equal(b.XListMapKeys, r.XListMapKeys) {
+			updateSchemaDiff(result, path, func(d *CRDSchemaDiff) {
+				d.ListMapKeys = &StringListChange{From: b.XListMapKeys, To: r.XListMapKeys}
+			})
+
+			// changing the keys of an existing map invalidates all managed fields
+			if baseType == listTypeMap && revisionType == listTypeMap {
+				result.BreakingChanges = append(result.BreakingChanges, BreakingChange{
+					ID:    ListMapKeysChangedID,
+					Level: checker.ERR,
+					Details: &ListMapKeysChangedMessage{
+						Path: path,
+						From: b.XListMapKeys,
+						To:   r.XListMapKeys,
+					},
+				})
+			}
+		}
+	})
+}
+
+// listType returns the effective list type; lists are atomic by default.
+func listType(schema *apiextensionsv1.JSONSchemaProps) string {
+	if schema.XListType == nil || *schema.XListType == "" {
+		return listTypeAtomic
+	}
+
+	return *schema.XListType
+}
+
+// listTypeChangeLevel classifies a list type transition:
+//
+//   - Going from a granular (set/map) to an atomic list changes field
+//     ownership for server-side apply, but existing objects remain valid.
+//   - Going from atomic to set forbids duplicate items, which existing
+//     objects might contain.
+//   - Going from atomic to map requires every item to have the map keys
+//     and breaks server-side apply merges of existing objects, the same
+//     is true for switching between set and map.
+func listTypeChangeLevel(from, to string) checker.Level {
+	switch {
+	case to == listTypeAtomic:
+		return checker.WARN
+	case from == listTypeAtomic && to == listTypeSet:
+		return checker.WARN
+	default:
+		return checker.ERR
+	}
+}
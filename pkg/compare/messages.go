@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"fmt"
+
+	"go.xrstf.de/crdiff/pkg/compare/oasdiff"
+)
+
+// These are the IDs of breaking changes that are detected by crdiff itself
+// instead of oasdiff, usually because they concern Kubernetes extensions.
+const (
+	ListTypeChangedID          = "list-type-changed"
+	ListMapKeysChangedID       = "list-map-keys-changed"
+	ValidationRuleAddedID      = "validation-rule-added"
+	ValidationRuleChangedID    = "validation-rule-changed"
+	UnknownFieldsPrunedID      = "unknown-fields-pruned"
+	EmbeddedResourceEnabledID  = "embedded-resource-enabled"
+	EmbeddedResourceDisabledID = "embedded-resource-disabled"
+	MapTypeChangedID           = "map-type-changed"
+)
+
+// NewBreakingChangeDetails returns a pointer to an empty details struct for
+// the given breaking change ID. Unknown IDs are assumed to be raw oasdiff
+// messages that crdiff cannot interpret.
+func NewBreakingChangeDetails(id string) interface{} {
+	switch id {
+	case ListTypeChangedID:
+		return &ListTypeChangedMessage{}
+	case ListMapKeysChangedID:
+		return &ListMapKeysChangedMessage{}
+	case ValidationRuleAddedID:
+		return &ValidationRuleAddedMessage{}
+	case ValidationRuleChangedID:
+		return &ValidationRuleChangedMessage{}
+	case UnknownFieldsPrunedID:
+		return &UnknownFieldsPrunedMessage{}
+	case EmbeddedResourceEnabledID:
+		return &EmbeddedResourceEnabledMessage{}
+	case EmbeddedResourceDisabledID:
+		return &EmbeddedResourceDisabledMessage{}
+	case MapTypeChangedID:
+		return &MapTypeChangedMessage{}
+	}
+
+	if msg := oasdiff.NewMessage(id); msg != nil {
+		return msg
+	}
+
+	return &oasdiff.LocalizedMessage{}
+}
+
+// DescribeBreakingChange returns a short description of the check that
+// produces breaking changes with the given ID.
+func DescribeBreakingChange(id string) string {
+	switch id {
+	case ListTypeChangedID:
+		return "The x-kubernetes-list-type of a list was changed."
+	case ListMapKeysChangedID:
+		return "The x-kubernetes-list-map-keys of a list were changed."
+	case ValidationRuleAddedID:
+		return "A CEL validation rule was added, which existing objects might violate."
+	case ValidationRuleChangedID:
+		return "A CEL validation rule was changed, which existing objects might violate."
+	case UnknownFieldsPrunedID:
+		return "Unknown fields that were previously preserved will be pruned."
+	case EmbeddedResourceEnabledID:
+		return "An object became an embedded resource and requires apiVersion and kind."
+	case EmbeddedResourceDisabledID:
+		return "An object is no longer an embedded resource and its apiVersion, kind and metadata will be pruned."
+	case MapTypeChangedID:
+		return "The x-kubernetes-map-type of an object was changed."
+	}
+
+	if description := oasdiff.Describe(id); description != "" {
+		return description
+	}
+
+	return fmt.Sprintf("The oasdiff check %q detected a breaking change.", id)
+}
+
+type ListTypeChangedMessage struct {
+	Path string `json:"path" yaml:"path"`
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+type ListMapKeysChangedMessage struct {
+	Path string   `json:"path" yaml:"path"`
+	From []string `json:"from" yaml:"from"`
+	To   []string `json:"to" yaml:"to"`
+}
+
+type ValidationRuleAddedMessage struct {
+	Path string `json:"path" yaml:"path"`
+	Rule string `json:"rule" yaml:"rule"`
+}
+
+type ValidationRuleChangedMessage struct {
+	Path string `json:"path" yaml:"path"`
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+type UnknownFieldsPrunedMessage struct {
+	Path string `json:"path" yaml:"path"`
+}
+
+type EmbeddedResourceEnabledMessage struct {
+	Path string `json:"path" yaml:"path"`
+}
+
+type EmbeddedResourceDisabledMessage struct {
+	Path   string   `json:"path" yaml:"path"`
+	Fields []string `json:"fields" yaml:"fields"`
+}
+
+type MapTypeChangedMessage struct {
+	Path string `json:"path" yaml:"path"`
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tufin/oasdiff/checker"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// compareNames compares everything in spec.names except for the kind, which
+// is part of the CRD identifier and so can never change.
+func compareNames(base, revision apiextensionsv1.CustomResourceDefinitionNames) []Change {
+	changes := []Change{}
+
+	// the plural is part of the resource URL, so changing it breaks every
+	// client and every RBAC rule
+	if base.Plural != revision.Plural {
+		changes = append(changes, Change{
+			Breaking:    true,
+			Level:       checker.ERR,
+			Description: fmt.Sprintf("changed plural name from %q to %q", base.Plural, revision.Plural),
+		})
+	}
+
+	// the apiserver defaults singular and listKind, so only the effective
+	// values matter
+	if base.Singular != revision.Singular {
+		changes = append(changes, defaultedNameChange("singular name", base.Singular, revision.Singular, strings.ToLower(base.Kind)))
+	}
+
+	if base.ListKind != revision.ListKind {
+		changes = append(changes, defaultedNameChange("list kind", base.ListKind, revision.ListKind, base.Kind+"List"))
+	}
+
+	changes = append(changes, compareNameList("short names", base.ShortNames, revision.ShortNames)...)
+	changes = append(changes, compareNameList("categories", base.Categories, revision.Categories)...)
+
+	return changes
+}
+
+// defaultedNameChange creates a change for names that are defaulted by the
+// apiserver. Setting or clearing them is harmless as long as the effective
+// value stays the same, but changing the effective value can break clients.
+func defaultedNameChange(attribute string, base, revision string, defaultValue string) Change {
+	effectiveBase := defaultName(base, defaultValue)
+	effectiveRevision := defaultName(revision, defaultValue)
+
+	if effectiveBase == effectiveRevision {
+		if base == "" {
+			return Change{
+				Description: fmt.Sprintf("explicitly set %s to its default %q", attribute, revision),
+			}
+		}
+
+		return Change{
+			Description: fmt.Sprintf("removed %s %q, which is the default", attribute, base),
+		}
+	}
+
+	return Change{
+		Breaking:    true,
+		Level:       checker.WARN,
+		Description: fmt.Sprintf("changed %s from %q to %q", attribute, effectiveBase, effectiveRevision),
+	}
+}
+
+func defaultName(name string, defaultValue string) string {
+	if name == "" {
+		return defaultValue
+	}
+
+	return name
+}
+
+// compareNameList compares short names or categories. Removing any of them
+// breaks kubectl usage, adding new ones is harmless.
+func compareNameList(attribute string, base, revision []string) []Change {
+	changes := []Change{}
+
+	baseSet := sets.New(base...)
+	revisionSet := sets.New(revision...)
+
+	if removed := baseSet.Difference(revisionSet); removed.Len() > 0 {
+		changes = append(changes, Change{
+			Breaking:    true,
+			Level:       checker.WARN,
+			Description: fmt.Sprintf("removed %s %s", attribute, strings.Join(sets.List(removed), ", ")),
+		})
+	}
+
+	if added := revisionSet.Difference(baseSet); added.Len() > 0 {
+		changes = append(changes, Change{
+			Description: fmt.Sprintf("added %s %s", attribute, strings.Join(sets.List(added), ", ")),
+		})
+	}
+
+	return changes
+}
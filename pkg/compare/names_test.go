@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestCompareNamesDefaults(t *testing.T) {
+	names := apiextensionsv1.CustomResourceDefinitionNames{
+		Kind:     "Thing",
+		Plural:   "things",
+		Singular: "thing",
+		ListKind: "ThingList",
+	}
+
+	cleared := names
+	cleared.Singular = ""
+	cleared.ListKind = ""
+
+	// clearing and setting defaulted names is harmless
+	for _, tc := range []struct {
+		base     apiextensionsv1.CustomResourceDefinitionNames
+		revision apiextensionsv1.CustomResourceDefinitionNames
+	}{
+		{base: names, revision: cleared},
+		{base: cleared, revision: names},
+	} {
+		changes := compareNames(tc.base, tc.revision)
+		if len(changes) != 2 {
+			t.Fatalf("Expected two changes, but got %+v", changes)
+		}
+
+		for _, change := range changes {
+			if change.Breaking {
+				t.Errorf("Expected %q to not be breaking.", change.Description)
+			}
+		}
+	}
+
+	// changing the effective value is breaking, even if the field was not set before
+	renamed := cleared
+	renamed.Singular = "thingy"
+	renamed.ListKind = "Things"
+
+	for _, base := range []apiextensionsv1.CustomResourceDefinitionNames{names, cleared} {
+		changes := compareNames(base, renamed)
+		if len(changes) != 2 {
+			t.Fatalf("Expected two changes, but got %+v", changes)
+		}
+
+		for _, change := range changes {
+			if !change.Breaking {
+				t.Errorf("Expected %q to be breaking.", change.Description)
+			}
+		}
+	}
+}
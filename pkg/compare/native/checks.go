@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package native
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/tufin/oasdiff/checker"
+	"github.com/tufin/oasdiff/diff"
+
+	"go.xrstf.de/crdiff/pkg/compare/oasdiff"
+	"go.xrstf.de/crdiff/pkg/crd"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// BreakingChange is a backwards-incompatible change. The IDs, levels and
+// details are identical to the ones produced by the oasdiff checker.
+type BreakingChange struct {
+	ID      string
+	Level   checker.Level
+	Details interface{}
+
+	// path is only used to sort changes
+	path string
+}
+
+// checkSchemaDiff implements the request property checks of oasdiff, treating
+// the CRD schema as the body of a request that creates or updates an object.
+func checkSchemaDiff(d *diff.SchemaDiff, base, revision *apiextensionsv1.JSONSchemaProps, path string) []BreakingChange {
+	if d == nil || base == nil || revision == nil {
+		return nil
+	}
+
+	changes := []BreakingChange{}
+	add := func(id string, level checker.Level, changePath string, details interface{}) {
+		changes = append(changes, BreakingChange{ID: id, Level: level, Details: details, path: changePath})
+	}
+
+	// the root schema itself is not a property
+	if path != "" {
+		changes = append(changes, checkProperty(d, revision, path)...)
+	}
+
+	if props := d.PropertiesDiff; props != nil {
+		for _, name := range props.Added {
+			if slices.Contains(revision.Required, name) {
+				p := crd.ChildPath(path, name)
+				add("new-required-request-property", checker.ERR, p, &oasdiff.NewRequiredPropertyMessage{Path: p})
+			}
+		}
+
+		for _, name := range props.Deleted {
+			p := crd.ChildPath(path, name)
+			add("request-property-removed", checker.WARN, p, &oasdiff.PropertyRemovedMessage{Path: p})
+		}
+	}
+
+	if required := d.RequiredDiff; required != nil {
+		for _, name := range required.Added {
+			_, inBase := base.Properties[name]
+			_, inRevision := revision.Properties[name]
+
+			// new and removed properties are handled above
+			if inBase && inRevision {
+				p := crd.ChildPath(path, name)
+				add("request-property-became-required", checker.ERR, p, &oasdiff.PropertyBecameRequiredMessage{Path: p})
+			}
+		}
+	}
+
+	if props := d.PropertiesDiff; props != nil {
+		for name, propDiff := range props.Modified {
+			baseProp := base.Properties[name]
+			revisionProp := revision.Properties[name]
+
+			changes = append(changes, checkSchemaDiff(propDiff, &baseProp, &revisionProp, crd.ChildPath(path, name))...)
+		}
+	}
+
+	changes = append(changes, checkSchemaDiff(d.ItemsDiff, itemsSchema(base), itemsSchema(revision), crd.ChildPath(path, "[]"))...)
+	changes = append(changes, checkSchemaDiff(d.AdditionalPropertiesDiff, additionalPropertiesSchema(base), additionalPropertiesSchema(revision), crd.ChildPath(path, "*"))...)
+
+	return changes
+}
+
+func checkProperty(d *diff.SchemaDiff, revision *apiextensionsv1.JSONSchemaProps, path string) []BreakingChange {
+	changes := []BreakingChange{}
+	add := func(id string, level checker.Level, details interface{}) {
+		changes = append(changes, BreakingChange{ID: id, Level: level, Details: details, path: path})
+	}
+
+	if d.TypeDiff != nil || d.FormatDiff != nil {
+		typeDiff := d.TypeDiff
+		if typeDiff == nil {
+			typeDiff = &diff.ValueDiff{From: revision.Type, To: revision.Type}
+		}
+
+		formatDiff := d.FormatDiff
+		if formatDiff == nil {
+			formatDiff = &diff.ValueDiff{From: revision.Format, To: revision.Format}
+		}
+
+		if isBreakingTypeChange(revision.Type, typeDiff, formatDiff) {
+			add("request-property-type-changed", checker.ERR, &oasdiff.PropertyTypeChangedMessage{
+				Path: path,
+				From: noneIfEmpty(typeDiff.From),
+				To:   noneIfEmpty(typeDiff.To),
+			})
+		}
+	}
+
+	if enumDiff := d.EnumDiff; enumDiff != nil {
+		if enumDiff.EnumAdded {
+			add("request-property-became-enum", checker.ERR, &oasdiff.PropertyBecameEnumMessage{Path: path})
+		} else {
+			for _, value := range enumDiff.Deleted {
+				add("request-property-enum-value-removed", checker.ERR, &oasdiff.PropertyEnumValueRemovedMessage{
+					Path:  path,
+					Value: fmt.Sprintf("%v", value),
+				})
+			}
+		}
+	}
+
+	if vd := d.MaxLengthDiff; vd != nil && vd.To != nil {
+		if vd.From == nil {
+			add("request-property-max-length-set", checker.WARN, &oasdiff.PropertyMaxLengthSetMessage{Path: path, Length: toInt(vd.To)})
+		} else if toInt(vd.To) < toInt(vd.From) {
+			add("request-property-max-length-decreased", checker.ERR, &oasdiff.PropertyMaxLengthDecreasedMessage{Path: path, Length: toInt(vd.To)})
+		}
+	}
+
+	if vd := d.MinLengthDiff; vd != nil && toInt(vd.To) > toInt(vd.From) {
+		add("request-property-min-length-increased", checker.ERR, &oasdiff.PropertyMinLengthIncreasedMessage{Path: path, From: toInt(vd.From), To: toInt(vd.To)})
+	}
+
+	if vd := d.MinItemsDiff; vd != nil && toInt(vd.To) > toInt(vd.From) {
+		add("request-property-min-items-increased", checker.ERR, &oasdiff.PropertyMinItemsIncreasedMessage{Path: path, From: toInt(vd.From), To: toInt(vd.To)})
+	}
+
+	if vd := d.MinDiff; vd != nil {
+		if to, ok := toFloat(vd.To); ok {
+			if from, ok := toFloat(vd.From); !ok {
+				add("request-property-min-set", checker.WARN, &oasdiff.PropertyMinSetMessage{Path: path, Value: to})
+			} else if to > from {
+				add("request-property-min-increased", checker.ERR, &oasdiff.PropertyMinIncreasedMessage{Path: path, Value: to})
+			}
+		}
+	}
+
+	if vd := d.MaxDiff; vd != nil {
+		if to, ok := toFloat(vd.To); ok {
+			if from, ok := toFloat(vd.From); !ok {
+				add("request-property-max-set", checker.WARN, &oasdiff.PropertyMaxSetMessage{Path: path, Value: to})
+			} else if to < from {
+				add("request-property-max-decreased", checker.ERR, &oasdiff.PropertyMaxDecreasedMessage{Path: path, Value: to})
+			}
+		}
+	}
+
+	if vd := d.PatternDiff; vd != nil {
+		from, _ := vd.From.(string)
+		to, _ := vd.To.(string)
+
+		switch {
+		case to == "":
+			// removing a pattern is not breaking
+		case from == "":
+			add("request-property-pattern-added", checker.WARN, &oasdiff.PropertyPatternAddedMessage{Path: path, Pattern: to})
+		case to != ".*":
+			add("request-property-pattern-changed", checker.WARN, &oasdiff.PropertyPatternChangedMessage{Path: path, From: from, To: to})
+		}
+	}
+
+	if vd := d.NullableDiff; vd != nil && vd.From == true {
+		add("request-property-became-not-nullable", checker.ERR, &oasdiff.PropertyBecameNotNullableMessage{Path: path})
+	}
+
+	return changes
+}
+
+// isBreakingTypeChange returns false if all previously valid values
+// remain valid, e.g. when an integer field becomes a number field.
+func isBreakingTypeChange(schemaType string, typeDiff, formatDiff *diff.ValueDiff) bool {
+	if typeDiff.From != typeDiff.To {
+		return !(typeDiff.To == "number" && typeDiff.From == "integer")
+	}
+
+	if formatDiff.From != formatDiff.To {
+		return !isFormatContained(schemaType, formatDiff.To, formatDiff.From)
+	}
+
+	return false
+}
+
+// isFormatContained checks if format2 is contained in format1.
+func isFormatContained(schemaType string, format1, format2 interface{}) bool {
+	switch schemaType {
+	case "number":
+		return format1 == "double" && format2 == "float"
+	case "integer":
+		return (format1 == "int64" && format2 == "int32") ||
+			(format1 == "bigint" && format2 == "int32") ||
+			(format1 == "bigint" && format2 == "int64")
+	case "string":
+		return format1 == "date-time" && (format2 == "date" || format2 == "time")
+	}
+
+	return false
+}
+
+func noneIfEmpty(value interface{}) string {
+	if s, ok := value.(string); ok && s != "" {
+		return s
+	}
+
+	return "none"
+}
+
+func toInt(value interface{}) int {
+	if u, ok := value.(uint64); ok {
+		return int(u)
+	}
+
+	return 0
+}
+
+// toFloat returns the value of a minimum/maximum constraint and false if
+// the constraint is not set.
+func toFloat(value interface{}) (float64, bool) {
+	f, ok := value.(float64)
+	return f, ok
+}
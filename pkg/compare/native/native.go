@@ -0,0 +1,372 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+// Package native implements a schema differ that works directly on
+// Kubernetes JSONSchemaProps, without converting schemas into OpenAPI
+// documents first. It produces the same diff structures and breaking
+// change messages as the oasdiff-based implementation, but reports
+// precise paths (e.g. ".spec.ports.[].name").
+//
+// Like oasdiff with its default configuration, this package does not diff
+// specification extensions, so the ExtensionsDiff of the results is always
+// empty. The x-kubernetes-* extensions are instead compared by the compare
+// package itself, regardless of the engine.
+package native
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/tufin/oasdiff/diff"
+	"github.com/tufin/oasdiff/utils"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// CompareSchemas returns the differences between both schemas (nil if
+// there are none) and all backwards-incompatible changes.
+func CompareSchemas(base, revision *apiextensionsv1.JSONSchemaProps) (*diff.SchemaDiff, []BreakingChange) {
+	schemaDiff := diffSchemas(base, revision)
+	if schemaDiff == nil {
+		return nil, nil
+	}
+
+	changes := checkSchemaDiff(schemaDiff, base, revision, "")
+	sortBreakingChanges(changes)
+
+	return schemaDiff, changes
+}
+
+func diffSchemas(base, revision *apiextensionsv1.JSONSchemaProps) *diff.SchemaDiff {
+	switch {
+	case base == nil && revision == nil:
+		return nil
+	case base == nil:
+		return &diff.SchemaDiff{SchemaAdded: true}
+	case revision == nil:
+		return &diff.SchemaDiff{SchemaDeleted: true}
+	}
+
+	result := &diff.SchemaDiff{
+		OneOfDiff:                       diffSchemaLists(base.OneOf, revision.OneOf),
+		AnyOfDiff:                       diffSchemaLists(base.AnyOf, revision.AnyOf),
+		AllOfDiff:                       diffSchemaLists(base.AllOf, revision.AllOf),
+		NotDiff:                         diffSchemas(base.Not, revision.Not),
+		TypeDiff:                        valueDiff(base.Type, revision.Type),
+		TitleDiff:                       valueDiff(base.Title, revision.Title),
+		FormatDiff:                      valueDiff(base.Format, revision.Format),
+		DescriptionDiff:                 valueDiff(base.Description, revision.Description),
+		ExternalDocsDiff:                diffExternalDocs(base.ExternalDocs, revision.ExternalDocs),
+		EnumDiff:                        diffEnums(base.Enum, revision.Enum),
+		DefaultDiff:                     valueDiff(decodeJSON(base.Default), decodeJSON(revision.Default)),
+		ExampleDiff:                     valueDiff(decodeJSON(base.Example), decodeJSON(revision.Example)),
+		AdditionalPropertiesAllowedDiff: valueDiff(additionalPropertiesAllowed(base), additionalPropertiesAllowed(revision)),
+		UniqueItemsDiff:                 valueDiff(base.UniqueItems, revision.UniqueItems),
+		ExclusiveMinDiff:                valueDiff(base.ExclusiveMinimum, revision.ExclusiveMinimum),
+		ExclusiveMaxDiff:                valueDiff(base.ExclusiveMaximum, revision.ExclusiveMaximum),
+		NullableDiff:                    valueDiff(base.Nullable, revision.Nullable),
+		MinDiff:                         valueDiff(derefFloat(base.Minimum), derefFloat(revision.Minimum)),
+		MaxDiff:                         valueDiff(derefFloat(base.Maximum), derefFloat(revision.Maximum)),
+		MultipleOfDiff:                  valueDiff(derefFloat(base.MultipleOf), derefFloat(revision.MultipleOf)),
+		MinLengthDiff:                   valueDiff(lowerBound(base.MinLength), lowerBound(revision.MinLength)),
+		MaxLengthDiff:                   valueDiff(upperBound(base.MaxLength), upperBound(revision.MaxLength)),
+		PatternDiff:                     valueDiff(base.Pattern, revision.Pattern),
+		MinItemsDiff:                    valueDiff(lowerBound(base.MinItems), lowerBound(revision.MinItems)),
+		MaxItemsDiff:                    valueDiff(upperBound(base.MaxItems), upperBound(revision.MaxItems)),
+		ItemsDiff:                       diffSchemas(itemsSchema(base), itemsSchema(revision)),
+		RequiredDiff:                    diffRequired(base.Required, revision.Required),
+		PropertiesDiff:                  diffProperties(base.Properties, revision.Properties),
+		MinPropsDiff:                    valueDiff(lowerBound(base.MinProperties), lowerBound(revision.MinProperties)),
+		MaxPropsDiff:                    valueDiff(upperBound(base.MaxProperties), upperBound(revision.MaxProperties)),
+		AdditionalPropertiesDiff:        diffSchemas(additionalPropertiesSchema(base), additionalPropertiesSchema(revision)),
+	}
+
+	if result.Empty() {
+		return nil
+	}
+
+	return result
+}
+
+func diffProperties(base, revision map[string]apiextensionsv1.JSONSchemaProps) *diff.SchemasDiff {
+	result := &diff.SchemasDiff{
+		Added:    utils.StringList{},
+		Deleted:  utils.StringList{},
+		Modified: diff.ModifiedSchemas{},
+	}
+
+	// ensure a stable order
+	for _, name := range sets.List(sets.KeySet(base)) {
+		revisionProp, exists := revision[name]
+		if !exists {
+			result.Deleted = append(result.Deleted, name)
+			continue
+		}
+
+		baseProp := base[name]
+		if d := diffSchemas(&baseProp, &revisionProp); d != nil {
+			result.Modified[name] = d
+		}
+	}
+
+	for _, name := range sets.List(sets.KeySet(revision)) {
+		if _, exists := base[name]; !exists {
+			result.Added = append(result.Added, name)
+		}
+	}
+
+	if result.Empty() {
+		return nil
+	}
+
+	return result
+}
+
+// diffSchemaLists mimics oasdiff's handling of inline schemas in anyOf/allOf/oneOf:
+// identical schemas are matched regardless of their position; if exactly one
+// schema was replaced, the two are diffed against each other.
+func diffSchemaLists(base, revision []apiextensionsv1.JSONSchemaProps) *diff.SchemaListDiff {
+	if len(base) == 0 && len(revision) == 0 {
+		return nil
+	}
+
+	deletedIdx := unmatchedSchemas(base, revision)
+	addedIdx := unmatchedSchemas(revision, base)
+
+	if len(deletedIdx) == 1 && len(addedIdx) == 1 {
+		d := diffSchemas(&base[deletedIdx[0]], &revision[addedIdx[0]])
+		if d == nil {
+			return nil
+		}
+
+		return &diff.SchemaListDiff{
+			Modified: diff.ModifiedSchemas{fmt.Sprintf("#%d", 1+deletedIdx[0]): d},
+		}
+	}
+
+	result := &diff.SchemaListDiff{}
+
+	for _, idx := range addedIdx {
+		result.Added = append(result.Added, fmt.Sprintf("RevisionSchema[%d]", idx))
+	}
+
+	for _, idx := range deletedIdx {
+		result.Deleted = append(result.Deleted, fmt.Sprintf("BaseSchema[%d]", idx))
+	}
+
+	if result.Empty() {
+		return nil
+	}
+
+	return result
+}
+
+// unmatchedSchemas returns the indexes of all schemas in a that have no
+// identical counterpart in b.
+func unmatchedSchemas(a, b []apiextensionsv1.JSONSchemaProps) []int {
+	matched := sets.New[int]()
+	unmatched := []int{}
+
+	for i := range a {
+		found := false
+
+		for j := range b {
+			if !matched.Has(j) && diffSchemas(&a[i], &b[j]) == nil {
+				matched.Insert(j)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			unmatched = append(unmatched, i)
+		}
+	}
+
+	return unmatched
+}
+
+func diffExternalDocs(base, revision *apiextensionsv1.ExternalDocumentation) *diff.ExternalDocsDiff {
+	switch {
+	case base == nil && revision == nil:
+		return nil
+	case base == nil:
+		return &diff.ExternalDocsDiff{Added: true}
+	case revision == nil:
+		return &diff.ExternalDocsDiff{Deleted: true}
+	}
+
+	result := &diff.ExternalDocsDiff{
+		DescriptionDiff: valueDiff(base.Description, revision.Description),
+		URLDiff:         valueDiff(base.URL, revision.URL),
+	}
+
+	if result.Empty() {
+		return nil
+	}
+
+	return result
+}
+
+func diffEnums(base, revision []apiextensionsv1.JSON) *diff.EnumDiff {
+	baseValues := decodeJSONList(base)
+	revisionValues := decodeJSONList(revision)
+
+	result := &diff.EnumDiff{
+		EnumAdded:   len(base) == 0 && len(revision) > 0,
+		EnumDeleted: len(base) > 0 && len(revision) == 0,
+		Added:       diff.EnumValues{},
+		Deleted:     diff.EnumValues{},
+	}
+
+	for _, v := range baseValues {
+		if !containsValue(revisionValues, v) {
+			result.Deleted = append(result.Deleted, v)
+		}
+	}
+
+	for _, v := range revisionValues {
+		if !containsValue(baseValues, v) {
+			result.Added = append(result.Added, v)
+		}
+	}
+
+	if result.Empty() {
+		return nil
+	}
+
+	return result
+}
+
+func diffRequired(base, revision []string) *diff.RequiredPropertiesDiff {
+	baseSet := sets.New(base...)
+	revisionSet := sets.New(revision...)
+
+	added := revisionSet.Difference(baseSet)
+	deleted := baseSet.Difference(revisionSet)
+
+	if added.Len() == 0 && deleted.Len() == 0 {
+		return nil
+	}
+
+	return &diff.RequiredPropertiesDiff{
+		StringsDiff: diff.StringsDiff{
+			Added:   sets.List(added),
+			Deleted: sets.List(deleted),
+		},
+	}
+}
+
+func valueDiff(base, revision interface{}) *diff.ValueDiff {
+	if reflect.DeepEqual(base, revision) {
+		return nil
+	}
+
+	return &diff.ValueDiff{
+		From: base,
+		To:   revision,
+	}
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func decodeJSON(value *apiextensionsv1.JSON) interface{} {
+	if value == nil || len(value.Raw) == 0 {
+		return nil
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(value.Raw, &decoded); err != nil {
+		return string(value.Raw)
+	}
+
+	return decoded
+}
+
+func decodeJSONList(values []apiextensionsv1.JSON) []interface{} {
+	result := []interface{}{}
+
+	for i := range values {
+		result = append(result, decodeJSON(&values[i]))
+	}
+
+	return result
+}
+
+func derefFloat(value *float64) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return *value
+}
+
+// lowerBound returns the value of a minLength/minItems/minProperties
+// constraint; not setting these is identical to setting them to 0.
+func lowerBound(value *int64) uint64 {
+	if value == nil || *value < 0 {
+		return 0
+	}
+
+	return uint64(*value)
+}
+
+// upperBound returns the value of a maxLength/maxItems/maxProperties
+// constraint, or nil if it is not set.
+func upperBound(value *int64) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return uint64(*value)
+}
+
+func itemsSchema(schema *apiextensionsv1.JSONSchemaProps) *apiextensionsv1.JSONSchemaProps {
+	if schema.Items == nil {
+		return nil
+	}
+
+	return schema.Items.Schema
+}
+
+func additionalPropertiesSchema(schema *apiextensionsv1.JSONSchemaProps) *apiextensionsv1.JSONSchemaProps {
+	if schema.AdditionalProperties == nil {
+		return nil
+	}
+
+	return schema.AdditionalProperties.Schema
+}
+
+func additionalPropertiesAllowed(schema *apiextensionsv1.JSONSchemaProps) interface{} {
+	if schema.AdditionalProperties == nil || schema.AdditionalProperties.Schema != nil {
+		return nil
+	}
+
+	return schema.AdditionalProperties.Allows
+}
+
+func sortBreakingChanges(changes []BreakingChange) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+
+		switch {
+		case a.Level != b.Level:
+			return a.Level > b.Level
+		case a.ID != b.ID:
+			return a.ID < b.ID
+		default:
+			return a.path < b.path
+		}
+	})
+}
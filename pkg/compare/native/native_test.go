@@ -0,0 +1,239 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package native
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tufin/oasdiff/checker"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
+
+func float64Ptr(value float64) *float64 {
+	return &value
+}
+
+func objectSchema(props map[string]apiextensionsv1.JSONSchemaProps, required ...string) *apiextensionsv1.JSONSchemaProps {
+	return &apiextensionsv1.JSONSchemaProps{
+		Type:       "object",
+		Properties: props,
+		Required:   required,
+	}
+}
+
+func TestCompareSchemasUnchanged(t *testing.T) {
+	schema := objectSchema(map[string]apiextensionsv1.JSONSchemaProps{
+		"name": {Type: "string", MinLength: int64Ptr(1)},
+		"ports": {
+			Type: "array",
+			Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+				Schema: &apiextensionsv1.JSONSchemaProps{Type: "integer"},
+			},
+		},
+	}, "name")
+
+	if d, changes := CompareSchemas(schema, schema.DeepCopy()); d != nil || len(changes) > 0 {
+		t.Errorf("Expected no changes, but got %+v and %+v", d, changes)
+	}
+}
+
+func TestCompareSchemasNormalization(t *testing.T) {
+	// not setting a lower bound is the same as setting it to 0
+	base := &apiextensionsv1.JSONSchemaProps{Type: "string"}
+	revision := &apiextensionsv1.JSONSchemaProps{Type: "string", MinLength: int64Ptr(0)}
+
+	if d, _ := CompareSchemas(base, revision); d != nil {
+		t.Errorf("Expected minLength=0 to equal an unset minLength, but got %+v", d)
+	}
+
+	// anyOf schemas are matched regardless of their order
+	base = &apiextensionsv1.JSONSchemaProps{AnyOf: []apiextensionsv1.JSONSchemaProps{{Type: "integer"}, {Type: "string"}}}
+	revision = &apiextensionsv1.JSONSchemaProps{AnyOf: []apiextensionsv1.JSONSchemaProps{{Type: "string"}, {Type: "integer"}}}
+
+	if d, _ := CompareSchemas(base, revision); d != nil {
+		t.Errorf("Expected reordered anyOf schemas to be identical, but got %+v", d)
+	}
+}
+
+func TestCompareSchemasExternalDocs(t *testing.T) {
+	base := &apiextensionsv1.JSONSchemaProps{
+		Type:         "string",
+		ExternalDocs: &apiextensionsv1.ExternalDocumentation{URL: "https://example.com/a"},
+	}
+
+	revision := base.DeepCopy()
+	revision.ExternalDocs.URL = "https://example.com/b"
+
+	d, changes := CompareSchemas(base, revision)
+	if d == nil || d.ExternalDocsDiff == nil || d.ExternalDocsDiff.URLDiff == nil {
+		t.Fatalf("Expected external docs URL change, but got %+v", d)
+	}
+
+	if len(changes) > 0 {
+		t.Errorf("Expected documentation changes to not be breaking, but got %+v", changes)
+	}
+
+	revision.ExternalDocs = nil
+
+	d, _ = CompareSchemas(base, revision)
+	if d == nil || d.ExternalDocsDiff == nil || !d.ExternalDocsDiff.Deleted {
+		t.Errorf("Expected external docs to be deleted, but got %+v", d)
+	}
+}
+
+func TestCompareSchemasBreakingChanges(t *testing.T) {
+	testcases := []struct {
+		name     string
+		base     apiextensionsv1.JSONSchemaProps
+		revision apiextensionsv1.JSONSchemaProps
+		required bool
+		expected []string
+	}{
+		{
+			name:     "type changed",
+			base:     apiextensionsv1.JSONSchemaProps{Type: "string"},
+			revision: apiextensionsv1.JSONSchemaProps{Type: "integer"},
+			expected: []string{"request-property-type-changed@.spec.field"},
+		},
+		{
+			name:     "integer widened to number",
+			base:     apiextensionsv1.JSONSchemaProps{Type: "integer"},
+			revision: apiextensionsv1.JSONSchemaProps{Type: "number"},
+		},
+		{
+			name:     "format widened",
+			base:     apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int32"},
+			revision: apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int64"},
+		},
+		{
+			name:     "format narrowed",
+			base:     apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int64"},
+			revision: apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int32"},
+			expected: []string{"request-property-type-changed@.spec.field"},
+		},
+		{
+			name:     "max length set",
+			base:     apiextensionsv1.JSONSchemaProps{Type: "string"},
+			revision: apiextensionsv1.JSONSchemaProps{Type: "string", MaxLength: int64Ptr(10)},
+			expected: []string{"request-property-max-length-set@.spec.field"},
+		},
+		{
+			name:     "max length increased",
+			base:     apiextensionsv1.JSONSchemaProps{Type: "string", MaxLength: int64Ptr(10)},
+			revision: apiextensionsv1.JSONSchemaProps{Type: "string", MaxLength: int64Ptr(20)},
+		},
+		{
+			name:     "min length increased",
+			base:     apiextensionsv1.JSONSchemaProps{Type: "string", MinLength: int64Ptr(1)},
+			revision: apiextensionsv1.JSONSchemaProps{Type: "string", MinLength: int64Ptr(2)},
+			expected: []string{"request-property-min-length-increased@.spec.field"},
+		},
+		{
+			name:     "minimum set and maximum decreased",
+			base:     apiextensionsv1.JSONSchemaProps{Type: "integer", Maximum: float64Ptr(10)},
+			revision: apiextensionsv1.JSONSchemaProps{Type: "integer", Minimum: float64Ptr(1), Maximum: float64Ptr(5)},
+			expected: []string{"request-property-max-decreased@.spec.field", "request-property-min-set@.spec.field"},
+		},
+		{
+			name:     "enum value removed",
+			base:     apiextensionsv1.JSONSchemaProps{Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"a"`)}, {Raw: []byte(`"b"`)}}},
+			revision: apiextensionsv1.JSONSchemaProps{Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"a"`)}}},
+			expected: []string{"request-property-enum-value-removed@.spec.field"},
+		},
+		{
+			name:     "pattern relaxed to match everything",
+			base:     apiextensionsv1.JSONSchemaProps{Type: "string", Pattern: "^[a-z]+$"},
+			revision: apiextensionsv1.JSONSchemaProps{Type: "string", Pattern: ".*"},
+		},
+		{
+			name:     "became not nullable",
+			base:     apiextensionsv1.JSONSchemaProps{Type: "string", Nullable: true},
+			revision: apiextensionsv1.JSONSchemaProps{Type: "string"},
+			expected: []string{"request-property-became-not-nullable@.spec.field"},
+		},
+		{
+			name:     "became required",
+			base:     apiextensionsv1.JSONSchemaProps{Type: "string"},
+			revision: apiextensionsv1.JSONSchemaProps{Type: "string"},
+			required: true,
+			expected: []string{"request-property-became-required@.spec.field"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			base := objectSchema(map[string]apiextensionsv1.JSONSchemaProps{
+				"spec": *objectSchema(map[string]apiextensionsv1.JSONSchemaProps{"field": tc.base}),
+			})
+
+			revisionSpec := objectSchema(map[string]apiextensionsv1.JSONSchemaProps{"field": tc.revision})
+			if tc.required {
+				revisionSpec.Required = []string{"field"}
+			}
+
+			revision := objectSchema(map[string]apiextensionsv1.JSONSchemaProps{"spec": *revisionSpec})
+
+			_, changes := CompareSchemas(base, revision)
+			assertBreakingChanges(t, changes, tc.expected)
+		})
+	}
+}
+
+func TestCompareSchemasProperties(t *testing.T) {
+	base := objectSchema(map[string]apiextensionsv1.JSONSchemaProps{
+		"ports": {
+			Type: "array",
+			Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+				Schema: objectSchema(map[string]apiextensionsv1.JSONSchemaProps{
+					"name":     {Type: "string"},
+					"protocol": {Type: "string"},
+				}),
+			},
+		},
+	})
+
+	revision := objectSchema(map[string]apiextensionsv1.JSONSchemaProps{
+		"ports": {
+			Type: "array",
+			Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+				Schema: objectSchema(map[string]apiextensionsv1.JSONSchemaProps{
+					"name": {Type: "string"},
+					"port": {Type: "integer"},
+				}, "port"),
+			},
+		},
+	})
+
+	_, changes := CompareSchemas(base, revision)
+
+	// list items use precise paths; the error comes first
+	assertBreakingChanges(t, changes, []string{
+		"new-required-request-property@.ports.[].port",
+		"request-property-removed@.ports.[].protocol",
+	})
+
+	if changes[0].Level != checker.ERR || changes[1].Level != checker.WARN {
+		t.Errorf("Expected an error and a warning, but got %+v", changes)
+	}
+}
+
+func assertBreakingChanges(t *testing.T, changes []BreakingChange, expected []string) {
+	t.Helper()
+
+	actual := []string{}
+	for _, change := range changes {
+		actual = append(actual, fmt.Sprintf("%s@%s", change.ID, change.path))
+	}
+
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected breaking changes %v, but got %v.", expected, actual)
+	}
+}
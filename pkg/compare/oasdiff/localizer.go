@@ -39,101 +39,219 @@ func jsonLocalizer(key string, args ...interface{}) string {
 	return string(encoded)
 }
 
-// Disect will return a concrete Message struct based on the key.
+// Disect will return a concrete Message struct based on the key, or an
+// error if the arguments do not match the expected types.
 // This is also where the concept of "properties" is turned into
 // "paths" inside a spec.
-func (m *LocalizedMessage) Disect() interface{} {
+func (m *LocalizedMessage) Disect() (interface{}, error) {
+	args := &messageArgs{args: m.Args}
+
+	var result interface{}
+
 	// This switch statement only contains keys relevant for diffs
 	// that can happen with crdiff, i.e. request header diffs are
 	// ignored here.
 	switch m.Key {
 	case "new-required-request-property":
-		return &NewRequiredPropertyMessage{
-			Path: toPath(m.Args[0]),
+		result = &NewRequiredPropertyMessage{
+			Path: args.pathArg(0),
 		}
 	case "request-property-became-required":
-		return &PropertyBecameRequiredMessage{
-			Path: toPath(m.Args[0]),
+		result = &PropertyBecameRequiredMessage{
+			Path: args.pathArg(0),
 		}
 	case "request-property-became-enum":
-		return &PropertyBecameEnumMessage{
-			Path: toPath(m.Args[0]),
+		result = &PropertyBecameEnumMessage{
+			Path: args.pathArg(0),
 		}
 	case "request-property-removed":
-		return &PropertyRemovedMessage{
-			Path: toPath(m.Args[0]),
+		result = &PropertyRemovedMessage{
+			Path: args.pathArg(0),
 		}
 	case "request-property-type-changed":
-		return &PropertyTypeChangedMessage{
-			Path: toPath(m.Args[0]),
-			From: m.Args[1].(string),
-			To:   m.Args[3].(string),
+		result = &PropertyTypeChangedMessage{
+			Path: args.pathArg(0),
+			From: args.stringArg(1),
+			To:   args.stringArg(3),
 		}
 	case "request-property-max-length-set":
-		return &PropertyMaxLengthSetMessage{
-			Path:   toPath(m.Args[0]),
-			Length: parseInt(m.Args[1]),
+		result = &PropertyMaxLengthSetMessage{
+			Path:   args.pathArg(0),
+			Length: args.intArg(1),
 		}
 	case "request-property-min-length-set":
-		return &PropertyMinLengthSetMessage{
-			Path:   toPath(m.Args[0]),
-			Length: parseInt(m.Args[1]),
+		result = &PropertyMinLengthSetMessage{
+			Path:   args.pathArg(0),
+			Length: args.intArg(1),
 		}
 	case "request-property-min-length-increased":
-		return &PropertyMinLengthIncreasedMessage{
-			Path: toPath(m.Args[0]),
-			From: parseInt(m.Args[1]),
-			To:   parseInt(m.Args[2]),
+		result = &PropertyMinLengthIncreasedMessage{
+			Path: args.pathArg(0),
+			From: args.intArg(1),
+			To:   args.intArg(2),
 		}
 	case "request-property-min-items-set":
-		return &PropertyMinItemsSetMessage{
-			Path:  toPath(m.Args[0]),
-			Items: parseInt(m.Args[1]),
+		result = &PropertyMinItemsSetMessage{
+			Path:  args.pathArg(0),
+			Items: args.intArg(1),
 		}
 	case "request-property-min-items-increased":
-		return &PropertyMinItemsIncreasedMessage{
-			Path: toPath(m.Args[0]),
-			From: parseInt(m.Args[1]),
-			To:   parseInt(m.Args[2]),
+		result = &PropertyMinItemsIncreasedMessage{
+			Path: args.pathArg(0),
+			From: args.intArg(1),
+			To:   args.intArg(2),
 		}
 	case "request-property-pattern-added":
-		return &PropertyPatternAddedMessage{
-			Pattern: m.Args[0].(string),
-			Path:    toPath(m.Args[1]),
+		result = &PropertyPatternAddedMessage{
+			Pattern: args.stringArg(0),
+			Path:    args.pathArg(1),
 		}
 	case "request-property-pattern-changed":
-		return &PropertyPatternChangedMessage{
-			Path: toPath(m.Args[0]),
-			From: m.Args[1].(string),
-			To:   m.Args[2].(string),
+		result = &PropertyPatternChangedMessage{
+			Path: args.pathArg(0),
+			From: args.stringArg(1),
+			To:   args.stringArg(2),
+		}
+	case "request-property-enum-value-removed":
+		result = &PropertyEnumValueRemovedMessage{
+			Value: args.valueArg(0),
+			Path:  args.pathArg(1),
+		}
+	case "request-property-max-length-decreased":
+		result = &PropertyMaxLengthDecreasedMessage{
+			Path:   args.pathArg(0),
+			Length: args.intArg(1),
+		}
+	case "request-property-min-set":
+		result = &PropertyMinSetMessage{
+			Path:  args.pathArg(0),
+			Value: args.floatArg(1),
+		}
+	case "request-property-min-increased":
+		result = &PropertyMinIncreasedMessage{
+			Path:  args.pathArg(0),
+			Value: args.floatArg(1),
+		}
+	case "request-property-max-set":
+		result = &PropertyMaxSetMessage{
+			Path:  args.pathArg(0),
+			Value: args.floatArg(1),
+		}
+	case "request-property-max-decreased":
+		result = &PropertyMaxDecreasedMessage{
+			Path:  args.pathArg(0),
+			Value: args.floatArg(1),
+		}
+	case "request-property-became-not-nullable":
+		result = &PropertyBecameNotNullableMessage{
+			Path: args.pathArg(0),
 		}
 	default:
-		return m
+		return m, nil
 	}
+
+	if args.err != nil {
+		return nil, fmt.Errorf("invalid arguments for %s: %w", m.Key, args.err)
+	}
+
+	return result, nil
 }
 
-func parseInt(v interface{}) int {
-	if i, ok := v.(int); ok {
-		return i
+// messageArgs gives typed access to the arguments of a localized message.
+// The first error is remembered and all further accesses return zero values,
+// so that a message can be parsed without checking every single argument.
+type messageArgs struct {
+	args []interface{}
+	err  error
+}
+
+func (a *messageArgs) get(i int) interface{} {
+	if a.err != nil {
+		return nil
 	}
 
-	if s, ok := v.(string); ok {
-		i, err := strconv.Atoi(s)
-		if err != nil {
-			panic(err)
-		}
+	if i >= len(a.args) {
+		a.err = fmt.Errorf("expected at least %d arguments, got %d", i+1, len(a.args))
+		return nil
+	}
+
+	return a.args[i]
+}
+
+func (a *messageArgs) valueArg(i int) string {
+	v := a.get(i)
+	if a.err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", v)
+}
+
+func (a *messageArgs) stringArg(i int) string {
+	v := a.get(i)
+	if a.err != nil {
+		return ""
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		a.err = fmt.Errorf("argument %d is %T, not a string", i, v)
+	}
 
-		return i
+	return s
+}
+
+func (a *messageArgs) pathArg(i int) string {
+	s := a.stringArg(i)
+	if a.err != nil {
+		return ""
 	}
 
-	panic(fmt.Sprintf("cannot parse %v (%T) as int", v, v))
+	return "." + strings.ReplaceAll(s, "/", ".")
 }
 
-func toPath(p interface{}) string {
-	s := p.(string)
-	s = strings.ReplaceAll(s, "/", ".")
+func (a *messageArgs) intArg(i int) int {
+	switch v := a.get(i).(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case string:
+		parsed, err := strconv.Atoi(v)
+		if err != nil && a.err == nil {
+			a.err = fmt.Errorf("argument %d: %w", i, err)
+		}
+
+		return parsed
+	default:
+		if a.err == nil {
+			a.err = fmt.Errorf("argument %d is %T, not an integer", i, v)
+		}
 
-	return "." + s
+		return 0
+	}
+}
+
+func (a *messageArgs) floatArg(i int) float64 {
+	switch v := a.get(i).(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case string:
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil && a.err == nil {
+			a.err = fmt.Errorf("argument %d: %w", i, err)
+		}
+
+		return parsed
+	default:
+		if a.err == nil {
+			a.err = fmt.Errorf("argument %d is %T, not a number", i, v)
+		}
+
+		return 0
+	}
 }
 
 type NewRequiredPropertyMessage struct {
@@ -195,3 +313,131 @@ type PropertyPatternChangedMessage struct {
 	From string `json:"from" yaml:"from"`
 	To   string `json:"to" yaml:"to"`
 }
+
+type PropertyEnumValueRemovedMessage struct {
+	Path  string `json:"path" yaml:"path"`
+	Value string `json:"value" yaml:"value"`
+}
+
+type PropertyMaxLengthDecreasedMessage struct {
+	Path   string `json:"path" yaml:"path"`
+	Length int    `json:"length" yaml:"length"`
+}
+
+type PropertyMinSetMessage struct {
+	Path  string  `json:"path" yaml:"path"`
+	Value float64 `json:"value" yaml:"value"`
+}
+
+type PropertyMinIncreasedMessage struct {
+	Path  string  `json:"path" yaml:"path"`
+	Value float64 `json:"value" yaml:"value"`
+}
+
+type PropertyMaxSetMessage struct {
+	Path  string  `json:"path" yaml:"path"`
+	Value float64 `json:"value" yaml:"value"`
+}
+
+type PropertyMaxDecreasedMessage struct {
+	Path  string  `json:"path" yaml:"path"`
+	Value float64 `json:"value" yaml:"value"`
+}
+
+type PropertyBecameNotNullableMessage struct {
+	Path string `json:"path" yaml:"path"`
+}
+
+// NewMessage returns a pointer to an empty message struct for the given
+// breaking change ID, or nil if the ID is not known.
+func NewMessage(id string) interface{} {
+	switch id {
+	case "new-required-request-property":
+		return &NewRequiredPropertyMessage{}
+	case "request-property-became-required":
+		return &PropertyBecameRequiredMessage{}
+	case "request-property-became-enum":
+		return &PropertyBecameEnumMessage{}
+	case "request-property-removed":
+		return &PropertyRemovedMessage{}
+	case "request-property-type-changed":
+		return &PropertyTypeChangedMessage{}
+	case "request-property-max-length-set":
+		return &PropertyMaxLengthSetMessage{}
+	case "request-property-min-length-set":
+		return &PropertyMinLengthSetMessage{}
+	case "request-property-min-length-increased":
+		return &PropertyMinLengthIncreasedMessage{}
+	case "request-property-min-items-set":
+		return &PropertyMinItemsSetMessage{}
+	case "request-property-min-items-increased":
+		return &PropertyMinItemsIncreasedMessage{}
+	case "request-property-pattern-added":
+		return &PropertyPatternAddedMessage{}
+	case "request-property-pattern-changed":
+		return &PropertyPatternChangedMessage{}
+	case "request-property-enum-value-removed":
+		return &PropertyEnumValueRemovedMessage{}
+	case "request-property-max-length-decreased":
+		return &PropertyMaxLengthDecreasedMessage{}
+	case "request-property-min-set":
+		return &PropertyMinSetMessage{}
+	case "request-property-min-increased":
+		return &PropertyMinIncreasedMessage{}
+	case "request-property-max-set":
+		return &PropertyMaxSetMessage{}
+	case "request-property-max-decreased":
+		return &PropertyMaxDecreasedMessage{}
+	case "request-property-became-not-nullable":
+		return &PropertyBecameNotNullableMessage{}
+	default:
+		return nil
+	}
+}
+
+// Describe returns a short description of the oasdiff check with the given
+// ID, or an empty string if the ID is not known.
+func Describe(id string) string {
+	switch id {
+	case "new-required-request-property":
+		return "A new required property was added."
+	case "request-property-became-required":
+		return "An optional property became required."
+	case "request-property-became-enum":
+		return "A property was restricted to an enum."
+	case "request-property-removed":
+		return "A property was removed."
+	case "request-property-type-changed":
+		return "The type or format of a property was changed."
+	case "request-property-max-length-set":
+		return "A maximum length was set for a property."
+	case "request-property-min-length-set":
+		return "A minimum length was set for a property."
+	case "request-property-min-length-increased":
+		return "The minimum length of a property was increased."
+	case "request-property-min-items-set":
+		return "A minimum number of items was set for a property."
+	case "request-property-min-items-increased":
+		return "The minimum number of items of a property was increased."
+	case "request-property-pattern-added":
+		return "A pattern was added to a property."
+	case "request-property-pattern-changed":
+		return "The pattern of a property was changed."
+	case "request-property-enum-value-removed":
+		return "A value was removed from a property's enum."
+	case "request-property-max-length-decreased":
+		return "The maximum length of a property was decreased."
+	case "request-property-min-set":
+		return "A minimum value was set for a property."
+	case "request-property-min-increased":
+		return "The minimum value of a property was increased."
+	case "request-property-max-set":
+		return "A maximum value was set for a property."
+	case "request-property-max-decreased":
+		return "The maximum value of a property was decreased."
+	case "request-property-became-not-nullable":
+		return "A property is no longer nullable."
+	default:
+		return ""
+	}
+}
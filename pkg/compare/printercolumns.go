@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// comparePrinterColumns compares the additionalPrinterColumns of a single
+// version; columns are identified by their name. Additionally all revision
+// columns are checked against the revision schema to find columns that
+// point to non-existing fields. Columns that were already broken in the base
+// are not reported again.
+func comparePrinterColumns(base, revision []apiextensionsv1.CustomResourceColumnDefinition, baseSchema, revisionSchema *apiextensionsv1.JSONSchemaProps, opt *CompareOptions) *PrinterColumnsDiff {
+	result := &PrinterColumnsDiff{}
+
+	baseColumns := map[string]PrinterColumn{}
+	for _, column := range base {
+		baseColumns[column.Name] = newPrinterColumn(column, opt)
+	}
+
+	revisionColumns := map[string]PrinterColumn{}
+	for _, column := range revision {
+		revisionColumns[column.Name] = newPrinterColumn(column, opt)
+	}
+
+	if !opt.BreakingOnly {
+		// iterate over the slices instead of the maps to keep the column order
+		for _, column := range base {
+			if _, exists := revisionColumns[column.Name]; !exists {
+				result.Removed = append(result.Removed, baseColumns[column.Name])
+			}
+		}
+
+		for _, column := range revision {
+			revisionColumn := revisionColumns[column.Name]
+
+			baseColumn, exists := baseColumns[column.Name]
+			if !exists {
+				result.Added = append(result.Added, revisionColumn)
+				continue
+			}
+
+			if baseColumn != revisionColumn {
+				result.Modified = append(result.Modified, PrinterColumnChange{
+					Name:     column.Name,
+					Base:     baseColumn,
+					Revision: revisionColumn,
+				})
+			}
+		}
+	}
+
+	for _, column := range revision {
+		if schemaHasPath(revisionSchema, column.JSONPath) {
+			continue
+		}
+
+		// only report columns that are newly broken
+		baseColumn, exists := baseColumns[column.Name]
+		if !exists || baseColumn.JSONPath != column.JSONPath || schemaHasPath(baseSchema, column.JSONPath) {
+			result.Broken = append(result.Broken, revisionColumns[column.Name])
+		}
+	}
+
+	if result.Empty() {
+		return nil
+	}
+
+	return result
+}
+
+func newPrinterColumn(column apiextensionsv1.CustomResourceColumnDefinition, opt *CompareOptions) PrinterColumn {
+	result := PrinterColumn{
+		Name:        column.Name,
+		Type:        column.Type,
+		Format:      column.Format,
+		Description: column.Description,
+		Priority:    column.Priority,
+		JSONPath:    column.JSONPath,
+	}
+
+	if opt.IgnoreDescriptions {
+		result.Description = ""
+	}
+
+	return result
+}
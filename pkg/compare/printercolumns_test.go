@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestComparePrinterColumnsIgnoreDescriptions(t *testing.T) {
+	schema := &apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"replicas": {Type: "integer"},
+				},
+			},
+		},
+	}
+
+	base := []apiextensionsv1.CustomResourceColumnDefinition{{
+		Name:        "Replicas",
+		Type:        "integer",
+		Description: "old description",
+		JSONPath:    ".spec.replicas",
+	}}
+
+	revision := []apiextensionsv1.CustomResourceColumnDefinition{{
+		Name:        "Replicas",
+		Type:        "integer",
+		Description: "new description",
+		JSONPath:    ".spec.replicas",
+	}}
+
+	if result := comparePrinterColumns(base, revision, schema, schema, &CompareOptions{IgnoreDescriptions: true}); result != nil {
+		t.Errorf("Expected description changes to be ignored, but got %+v", result)
+	}
+
+	result := comparePrinterColumns(base, revision, schema, schema, &CompareOptions{})
+	if result == nil || len(result.Modified) != 1 {
+		t.Fatalf("Expected exactly one modified column, but got %+v", result)
+	}
+
+	// other changes must still be detected
+	revision[0].Priority = 1
+
+	result = comparePrinterColumns(base, revision, schema, schema, &CompareOptions{IgnoreDescriptions: true})
+	if result == nil || len(result.Modified) != 1 {
+		t.Fatalf("Expected exactly one modified column, but got %+v", result)
+	}
+
+	if change := result.Modified[0]; change.Base.Description != change.Revision.Description {
+		t.Errorf("Expected descriptions to be ignored, but got %q and %q", change.Base.Description, change.Revision.Description)
+	}
+}
+
+func TestComparePrinterColumnsAlreadyBroken(t *testing.T) {
+	schema := &apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"spec": {Type: "object"},
+		},
+	}
+
+	columns := []apiextensionsv1.CustomResourceColumnDefinition{{
+		Name:     "Ready",
+		Type:     "string",
+		JSONPath: ".status.ready",
+	}}
+
+	if result := comparePrinterColumns(columns, columns, schema, schema, &CompareOptions{}); result != nil {
+		t.Errorf("Expected no changes when comparing a CRD with itself, but got %+v", result)
+	}
+
+	// a column that only breaks with the revision schema must be reported
+	baseSchema := schema.DeepCopy()
+	baseSchema.Properties["status"] = apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"ready": {Type: "string"},
+		},
+	}
+
+	result := comparePrinterColumns(columns, columns, baseSchema, schema, &CompareOptions{})
+	if result == nil || len(result.Broken) != 1 {
+		t.Fatalf("Expected exactly one broken column, but got %+v", result)
+	}
+
+	// as must columns whose path was changed to a non-existing field
+	revision := []apiextensionsv1.CustomResourceColumnDefinition{columns[0]}
+	revision[0].JSONPath = ".status.phase"
+
+	result = comparePrinterColumns(columns, revision, schema, schema, &CompareOptions{})
+	if result == nil || len(result.Broken) != 1 || len(result.Modified) != 1 {
+		t.Fatalf("Expected one modified and broken column, but got %+v", result)
+	}
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"github.com/tufin/oasdiff/checker"
+
+	"go.xrstf.de/crdiff/pkg/crd"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// comparePruning detects changes that make the apiserver prune data which
+// was previously preserved. Pruning happens silently, so users lose data
+// without ever seeing an error. This happens when
+//
+//   - x-kubernetes-preserve-unknown-fields is disabled on a field, or
+//   - a new object property is declared below a field that preserves unknown
+//     fields, because the preservation does not apply to nested properties.
+//
+// Enabling x-kubernetes-preserve-unknown-fields is reported as well, but is
+// not breaking.
+func comparePruning(result *CRDVersionDiff, base, revision *apiextensionsv1.JSONSchemaProps) {
+	walkSchemas(base, revision, "", func(path string, b, r *apiextensionsv1.JSONSchemaProps) {
+		basePreserves := preservesUnknownFields(b)
+		revisionPreserves := preservesUnknownFields(r)
+
+		if basePreserves != revisionPreserves {
+			updateSchemaDiff(result, path, func(d *CRDSchemaDiff) {
+				d.PreserveUnknownFields = &BoolChange{From: basePreserves, To: revisionPreserves}
+			})
+
+			if basePreserves {
+				result.BreakingChanges = append(result.BreakingChanges, newUnknownFieldsPrunedChange(path))
+			}
+		}
+
+		if !basePreserves {
+			return
+		}
+
+		pruned := []string{}
+
+		for _, name := range sets.List(sets.KeySet(r.Properties)) {
+			if _, exists := b.Properties[name]; exists {
+				continue
+			}
+
+			prop := r.Properties[name]
+			if isObject(&prop) && !preservesUnknownFields(&prop) {
+				pruned = append(pruned, name)
+				result.BreakingChanges = append(result.BreakingChanges, newUnknownFieldsPrunedChange(crd.ChildPath(path, name)))
+			}
+		}
+
+		if len(pruned) > 0 {
+			updateSchemaDiff(result, path, func(d *CRDSchemaDiff) {
+				d.PrunedProperties = pruned
+			})
+		}
+	})
+}
+
+// comparePreserveUnknownFields compares the CRD-wide spec.preserveUnknownFields,
+// which disables pruning entirely (and is the default for v1beta1 CRDs).
+func comparePreserveUnknownFields(base, revision bool) []Change {
+	switch {
+	case base && !revision:
+		return []Change{{
+			Breaking:    true,
+			Level:       checker.ERR,
+			Description: "disabled spec.preserveUnknownFields; unknown fields in all objects will be pruned",
+		}}
+
+	case !base && revision:
+		return []Change{{
+			Description: "enabled spec.preserveUnknownFields; unknown fields will not be pruned anymore",
+		}}
+	}
+
+	return nil
+}
+
+func newUnknownFieldsPrunedChange(path string) BreakingChange {
+	return BreakingChange{
+		ID:    UnknownFieldsPrunedID,
+		Level: checker.ERR,
+		Details: &UnknownFieldsPrunedMessage{
+			Path: path,
+		},
+	}
+}
+
+func preservesUnknownFields(schema *apiextensionsv1.JSONSchemaProps) bool {
+	return schema.XPreserveUnknownFields != nil && *schema.XPreserveUnknownFields
+}
+
+// isObject returns true for object schemas with a fixed set of properties.
+// Maps (objects using additionalProperties) keep all their keys and are
+// therefore not affected by pruning.
+func isObject(schema *apiextensionsv1.JSONSchemaProps) bool {
+	if ap := schema.AdditionalProperties; ap != nil && (ap.Allows || ap.Schema != nil) {
+		return false
+	}
+
+	return schema.Type == "object" || len(schema.Properties) > 0
+}
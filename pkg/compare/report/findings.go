@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/gookit/color"
+	"github.com/tufin/oasdiff/checker"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// These are the rule IDs for breaking changes that are not reported by
+// any schema check.
+const (
+	crdChangedRuleID          = "crd-changed"
+	versionChangedRuleID      = "version-changed"
+	versionRemovedRuleID      = "version-removed"
+	printerColumnBrokenRuleID = "printer-column-broken"
+)
+
+// describeRule returns a short description for the given rule ID.
+func describeRule(ruleID string) string {
+	switch ruleID {
+	case crdChangedRuleID:
+		return "A breaking change affecting the entire CRD, like changing its scope."
+	case versionChangedRuleID:
+		return "A breaking change affecting a CRD version, like disabling it."
+	case versionRemovedRuleID:
+		return "A CRD version was removed."
+	case printerColumnBrokenRuleID:
+		return "A printer column points to a field that does not exist."
+	default:
+		return compare.DescribeBreakingChange(ruleID)
+	}
+}
+
+// Finding is a single breaking change, flattened for machine-readable
+// output formats and custom templates.
+type Finding struct {
+	// CRD is the identifier of the affected CRD, e.g. "example.com/Thing".
+	CRD string
+	// Version is the affected CRD version; it is empty for changes to the
+	// entire CRD.
+	Version string
+	// Path is the affected schema path (e.g. ".spec.name"); it is empty for
+	// changes to the entire CRD or version.
+	Path string
+	// RuleID identifies the kind of breaking change, e.g.
+	// "request-property-max-length-decreased".
+	RuleID string
+	Level  checker.Level
+	// Message is a plain text description, without any colors.
+	Message string
+	// Location is the position of the change in the revision's files; it
+	// is nil if the source of the CRD is unknown.
+	Location *Location
+}
+
+// Findings returns all breaking changes in the report in a stable order.
+func (r *Report) Findings() []Finding {
+	defer disableColors()()
+
+	result := []Finding{}
+
+	for _, crdIdentifier := range sets.List(sets.KeySet(r.Diffs)) {
+		crdDiff := r.Diffs[crdIdentifier]
+
+		add := func(version string, path string, ruleID string, level checker.Level, message string) {
+			result = append(result, Finding{
+				CRD:      crdIdentifier,
+				Version:  version,
+				Path:     path,
+				RuleID:   ruleID,
+				Level:    level,
+				Message:  message,
+				Location: r.Locate(crdIdentifier, version, path),
+			})
+		}
+
+		for _, change := range crdDiff.General {
+			if change.Breaking {
+				add("", "", crdChangedRuleID, change.BreakingLevel(), capitalize(change.Description)+".")
+			}
+		}
+
+		for _, version := range crdDiff.DeletedVersions {
+			add(version, "", versionRemovedRuleID, checker.ERR, fmt.Sprintf("Version %s was removed.", version))
+		}
+
+		for _, version := range sets.List(sets.KeySet(crdDiff.ChangedVersions)) {
+			versionDiff := crdDiff.ChangedVersions[version]
+
+			for _, change := range versionDiff.General {
+				if change.Breaking {
+					add(version, "", versionChangedRuleID, change.BreakingLevel(), capitalize(change.Description)+".")
+				}
+			}
+
+			if columns := versionDiff.PrinterColumns; columns != nil {
+				for _, column := range columns.Broken {
+					message := fmt.Sprintf("Printer column %s points to %s, which does not exist in the schema.", column.Name, column.JSONPath)
+					add(version, "", printerColumnBrokenRuleID, checker.WARN, message)
+				}
+			}
+
+			for _, bc := range versionDiff.BreakingChanges {
+				add(version, breakingChangePath(bc), bc.ID, bc.Level, stripChangeMarker(renderBreakingChange(bc)))
+			}
+		}
+	}
+
+	return result
+}
+
+// breakingChangePath returns the schema path a breaking change refers to,
+// or an empty string if the change's details do not contain a path.
+func breakingChangePath(bc compare.BreakingChange) string {
+	var details struct {
+		Path string `json:"path"`
+	}
+
+	// all details structs name their path the same way
+	if encoded, err := json.Marshal(bc.Details); err == nil {
+		_ = json.Unmarshal(encoded, &details)
+	}
+
+	return details.Path
+}
+
+// findingScope returns the CRD and version a finding belongs to.
+func findingScope(f Finding) string {
+	if f.Version == "" {
+		return f.CRD
+	}
+
+	return f.CRD + " " + f.Version
+}
+
+// findingSubject is like findingScope, but also includes the schema path.
+func findingSubject(f Finding) string {
+	subject := findingScope(f)
+
+	if f.Path != "" {
+		subject += " " + f.Path
+	}
+
+	return subject
+}
+
+// relativeFilename returns the filename relative to the current working
+// directory, which for CI systems is usually the repository root, using
+// forward slashes. Files outside of it keep their absolute path.
+func relativeFilename(filename string) string {
+	if filepath.IsAbs(filename) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, filename); err == nil && !isOutside(rel) {
+				filename = rel
+			}
+		}
+	}
+
+	return filepath.ToSlash(filepath.Clean(filename))
+}
+
+// isOutside returns true if the relative path points to a parent directory.
+func isOutside(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// disableColors turns off ANSI colors and returns a function that
+// restores the previous state. This is required whenever the text
+// renderers are reused for other output formats.
+func disableColors() func() {
+	enabled := color.Enable
+	color.Enable = false
+
+	return func() {
+		color.Enable = enabled
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return string(unicode.ToUpper(rune(s[0]))) + s[1:]
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+type checkstyleResult struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// RenderCheckstyle renders all breaking changes as a Checkstyle XML report,
+// grouped by the file they were found in. Changes without a known file are
+// grouped by their CRD instead.
+func (r *Report) RenderCheckstyle() ([]byte, error) {
+	errors := map[string][]checkstyleError{}
+
+	for _, f := range r.Findings() {
+		filename := f.CRD
+		line := 0
+
+		if f.Location != nil && f.Location.Filename != "" {
+			filename = relativeFilename(f.Location.Filename)
+			line = f.Location.Line
+		}
+
+		errors[filename] = append(errors[filename], checkstyleError{
+			Line:     line,
+			Severity: levelName(f.Level),
+			Message:  fmt.Sprintf("%s: %s", findingScope(f), f.Message),
+			Source:   "crdiff." + f.RuleID,
+		})
+	}
+
+	result := checkstyleResult{
+		Version: "4.3",
+	}
+
+	for _, filename := range sets.List(sets.KeySet(errors)) {
+		result.Files = append(result.Files, checkstyleFile{
+			Name:   filename,
+			Errors: errors[filename],
+		})
+	}
+
+	encoded, err := xml.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(xml.Header + string(encoded) + "\n"), nil
+}
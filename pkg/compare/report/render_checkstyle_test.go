@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestRenderCheckstyle(t *testing.T) {
+	output, err := testReport().RenderCheckstyle()
+	if err != nil {
+		t.Fatalf("Failed to render Checkstyle report: %v", err)
+	}
+
+	compareGoldenFile(t, "testdata/report.checkstyle.xml", output)
+}
+
+func TestRenderCheckstyleWithoutSource(t *testing.T) {
+	r := testReport()
+	r.Sources = nil
+
+	output, err := r.RenderCheckstyle()
+	if err != nil {
+		t.Fatalf("Failed to render Checkstyle report: %v", err)
+	}
+
+	var result checkstyleResult
+	if err := xml.Unmarshal(output, &result); err != nil {
+		t.Fatalf("Failed to decode Checkstyle report: %v", err)
+	}
+
+	// without files, errors are grouped by CRD and have no line
+	files := map[string]int{}
+	for _, file := range result.Files {
+		files[file.Name] = len(file.Errors)
+
+		for _, e := range file.Errors {
+			if e.Line != 0 {
+				t.Errorf("Expected no line for %q, but got %d.", e.Message, e.Line)
+			}
+		}
+	}
+
+	if len(files) != 2 || files["example.com/Removed"] != 1 || files["example.com/Thing"] != 4 {
+		t.Errorf("Expected errors to be grouped by CRD, but got %v.", files)
+	}
+}
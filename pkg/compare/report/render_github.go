@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tufin/oasdiff/checker"
+)
+
+// RenderGitHubActions renders all breaking changes as GitHub Actions
+// workflow commands, which GitHub shows as annotations next to the
+// affected lines in pull requests.
+func (r *Report) RenderGitHubActions() string {
+	var out strings.Builder
+
+	for _, f := range r.Findings() {
+		properties := []string{}
+
+		if f.Location != nil && f.Location.Filename != "" {
+			properties = append(properties, "file="+escapeWorkflowProperty(relativeFilename(f.Location.Filename)))
+
+			if f.Location.Line > 0 {
+				properties = append(properties, fmt.Sprintf("line=%d", f.Location.Line))
+			}
+		}
+
+		properties = append(properties, "title="+escapeWorkflowProperty(fmt.Sprintf("crdiff: %s", f.RuleID)))
+
+		fmt.Fprintf(
+			&out,
+			"::%s %s::%s\n",
+			workflowCommand(f.Level),
+			strings.Join(properties, ","),
+			escapeWorkflowData(fmt.Sprintf("%s: %s", findingScope(f), f.Message)),
+		)
+	}
+
+	return out.String()
+}
+
+func workflowCommand(level checker.Level) string {
+	switch {
+	case level >= checker.ERR:
+		return "error"
+	case level == checker.WARN:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+var (
+	workflowDataEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+	)
+
+	workflowPropertyEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+		":", "%3A",
+		",", "%2C",
+	)
+)
+
+func escapeWorkflowData(s string) string {
+	return workflowDataEscaper.Replace(s)
+}
+
+func escapeWorkflowProperty(s string) string {
+	return workflowPropertyEscaper.Replace(s)
+}
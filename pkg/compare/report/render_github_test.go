@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tufin/oasdiff/checker"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+	"go.xrstf.de/crdiff/pkg/crd"
+)
+
+func TestRenderGitHubActions(t *testing.T) {
+	expected := strings.Join([]string{
+		"::error file=base/removed.yaml,line=1,title=crdiff%3A crd-changed::example.com/Removed: CRD has been removed.",
+		"::error file=crds/thing.yaml,line=10,title=crdiff%3A version-removed::example.com/Thing v1alpha1: Version v1alpha1 was removed.",
+		"::warning file=crds/thing.yaml,line=20,title=crdiff%3A version-changed::example.com/Thing v1: Version is no longer served.",
+		"::warning file=crds/thing.yaml,line=20,title=crdiff%3A printer-column-broken::example.com/Thing v1: Printer column Ready points to .status.ready, which does not exist in the schema.",
+		"::error file=crds/thing.yaml,line=40,title=crdiff%3A request-property-max-length-decreased::example.com/Thing v1: Maximum length of .spec.name was decreased to 15.",
+		"",
+	}, "\n")
+
+	if output := testReport().RenderGitHubActions(); output != expected {
+		t.Errorf("Expected\n\n%s\n\nbut got\n\n%s", expected, output)
+	}
+}
+
+func TestRenderGitHubActionsEscaping(t *testing.T) {
+	r := &Report{
+		Diffs: map[string]compare.CRDDiff{
+			"example.com/Thing": {
+				General: []compare.Change{{
+					Breaking:    true,
+					Level:       checker.WARN,
+					Description: "scope changed: 100% different,\nreally",
+				}},
+			},
+		},
+		Sources: map[string]*crd.Source{
+			"example.com/Thing": {Filename: "crds/a,b:c%.yaml", Line: 3},
+		},
+	}
+
+	expected := "::warning file=crds/a%2Cb%3Ac%25.yaml,line=3,title=crdiff%3A crd-changed::example.com/Thing: Scope changed: 100%25 different,%0Areally.\n"
+
+	if output := r.RenderGitHubActions(); output != expected {
+		t.Errorf("Expected\n\n%s\n\nbut got\n\n%s", expected, output)
+	}
+}
+
+func TestEscapeWorkflowCommands(t *testing.T) {
+	testcases := []struct {
+		input    string
+		data     string
+		property string
+	}{
+		{input: "plain", data: "plain", property: "plain"},
+		{input: "50%", data: "50%25", property: "50%25"},
+		{input: "a\r\nb", data: "a%0D%0Ab", property: "a%0D%0Ab"},
+		{input: "a:b,c", data: "a:b,c", property: "a%3Ab%2Cc"},
+		// already escaped sequences must be escaped again
+		{input: "%0A", data: "%250A", property: "%250A"},
+	}
+
+	for _, tc := range testcases {
+		if result := escapeWorkflowData(tc.input); result != tc.data {
+			t.Errorf("Expected data %q to be escaped as %q, but got %q.", tc.input, tc.data, result)
+		}
+
+		if result := escapeWorkflowProperty(tc.input); result != tc.property {
+			t.Errorf("Expected property %q to be escaped as %q, but got %q.", tc.input, tc.property, result)
+		}
+	}
+}
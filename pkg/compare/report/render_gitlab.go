@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/tufin/oasdiff/checker"
+)
+
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// RenderGitLabCodeQuality renders all breaking changes as a GitLab Code
+// Quality report, which GitLab shows inline in merge requests.
+func (r *Report) RenderGitLabCodeQuality() ([]byte, error) {
+	issues := []codeQualityIssue{}
+
+	for _, f := range r.Findings() {
+		location := codeQualityLocation{
+			// GitLab requires a path and a line, even if we cannot tell them;
+			// the CRD identifier at least tells users what to look for
+			Path:  f.CRD,
+			Lines: codeQualityLines{Begin: 1},
+		}
+
+		if f.Location != nil {
+			if f.Location.Filename != "" {
+				location.Path = relativeFilename(f.Location.Filename)
+			}
+
+			if f.Location.Line > 0 {
+				location.Lines.Begin = f.Location.Line
+			}
+		}
+
+		issues = append(issues, codeQualityIssue{
+			Description: fmt.Sprintf("%s: %s", findingScope(f), f.Message),
+			CheckName:   f.RuleID,
+			Fingerprint: codeQualityFingerprint(f),
+			Severity:    codeQualitySeverity(f.Level),
+			Location:    location,
+		})
+	}
+
+	encoded, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(encoded, '\n'), nil
+}
+
+func codeQualitySeverity(level checker.Level) string {
+	switch {
+	case level >= checker.ERR:
+		return "critical"
+	case level == checker.WARN:
+		return "major"
+	default:
+		return "info"
+	}
+}
+
+// codeQualityFingerprint identifies a finding across pipeline runs, so
+// GitLab can tell which issues are new in a merge request. It must not
+// depend on the line, which changes whenever the file is edited.
+func codeQualityFingerprint(f Finding) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s", f.RuleID, findingSubject(f), f.Message)))
+	return hex.EncodeToString(hash[:])
+}
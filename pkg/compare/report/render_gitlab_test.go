@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestRenderGitLabCodeQuality(t *testing.T) {
+	issues := renderCodeQualityIssues(t, testReport())
+
+	expected := []struct {
+		checkName string
+		severity  string
+		path      string
+		line      int
+	}{
+		{checkName: crdChangedRuleID, severity: "critical", path: "base/removed.yaml", line: 1},
+		{checkName: versionRemovedRuleID, severity: "critical", path: "crds/thing.yaml", line: 10},
+		{checkName: versionChangedRuleID, severity: "major", path: "crds/thing.yaml", line: 20},
+		{checkName: printerColumnBrokenRuleID, severity: "major", path: "crds/thing.yaml", line: 20},
+		{checkName: "request-property-max-length-decreased", severity: "critical", path: "crds/thing.yaml", line: 40},
+	}
+
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues, but got %+v.", len(expected), issues)
+	}
+
+	fingerprints := sets.New[string]()
+
+	for i, issue := range issues {
+		e := expected[i]
+
+		if issue.CheckName != e.checkName || issue.Severity != e.severity {
+			t.Errorf("Expected issue #%d to be a %s %q, but got a %s %q.", i, e.severity, e.checkName, issue.Severity, issue.CheckName)
+		}
+
+		if issue.Location.Path != e.path || issue.Location.Lines.Begin != e.line {
+			t.Errorf("Expected issue #%d to point to %s:%d, but got %+v.", i, e.path, e.line, issue.Location)
+		}
+
+		fingerprints.Insert(issue.Fingerprint)
+	}
+
+	if fingerprints.Len() != len(issues) {
+		t.Errorf("Expected every issue to have a unique fingerprint, but got %v.", sets.List(fingerprints))
+	}
+}
+
+func TestRenderGitLabCodeQualityWithoutSource(t *testing.T) {
+	r := testReport()
+	r.Sources = nil
+
+	for _, issue := range renderCodeQualityIssues(t, r) {
+		// GitLab rejects issues without a path
+		if issue.Location.Path == "" {
+			t.Errorf("Issue %q has no path.", issue.Description)
+		}
+
+		if issue.Location.Lines.Begin != 1 {
+			t.Errorf("Expected issue %q to point to the first line, but got %d.", issue.Description, issue.Location.Lines.Begin)
+		}
+	}
+}
+
+func TestCodeQualityFingerprint(t *testing.T) {
+	original := testReport()
+
+	// moving CRDs around in their files must not change the fingerprints
+	moved := testReport()
+	for _, source := range moved.Sources {
+		source.Line += 100
+		for _, lines := range source.SchemaLines {
+			for path := range lines {
+				lines[path] += 100
+			}
+		}
+	}
+
+	originalIssues := renderCodeQualityIssues(t, original)
+	movedIssues := renderCodeQualityIssues(t, moved)
+
+	for i := range originalIssues {
+		if originalIssues[i].Location == movedIssues[i].Location {
+			t.Fatalf("Expected issue #%d to have moved, but it is still at %+v.", i, originalIssues[i].Location)
+		}
+
+		if originalIssues[i].Fingerprint != movedIssues[i].Fingerprint {
+			t.Errorf("Expected issue #%d to keep its fingerprint after moving it.", i)
+		}
+	}
+
+	// fingerprints must be stable across crdiff releases, otherwise GitLab
+	// considers all issues to be new
+	f := original.Findings()[0]
+	if fingerprint := codeQualityFingerprint(f); fingerprint != "8641a757ec480fabca5476002ced38e58636927d1279cd6f466cd413e3a0010d" {
+		t.Errorf("Fingerprint for %q has changed to %q.", f.Message, fingerprint)
+	}
+
+	// but the fingerprint must depend on what changed
+	other := f
+	other.Message = "something else"
+	if codeQualityFingerprint(f) == codeQualityFingerprint(other) {
+		t.Error("Expected different findings to have different fingerprints.")
+	}
+}
+
+func renderCodeQualityIssues(t *testing.T, r *Report) []codeQualityIssue {
+	t.Helper()
+
+	encoded, err := r.RenderGitLabCodeQuality()
+	if err != nil {
+		t.Fatalf("Failed to render code quality report: %v", err)
+	}
+
+	var issues []codeQualityIssue
+	if err := json.Unmarshal(encoded, &issues); err != nil {
+		t.Fatalf("Failed to decode code quality report: %v", err)
+	}
+
+	return issues
+}
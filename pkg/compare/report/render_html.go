@@ -0,0 +1,598 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+
+	"github.com/gookit/color"
+	"github.com/tufin/oasdiff/checker"
+	"github.com/tufin/oasdiff/diff"
+
+	"go.xrstf.de/crdiff/pkg/colors"
+	"go.xrstf.de/crdiff/pkg/compare"
+	"go.xrstf.de/crdiff/pkg/crd"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+//go:embed render_html.tmpl
+var htmlTemplateSource string
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"actionClass": actionClass,
+	"levelName":   levelName,
+	"styles":      htmlStyles,
+}).Parse(htmlTemplateSource))
+
+// htmlColors maps the terminal colors used in pkg/colors to colors that are
+// readable on a white background.
+var htmlColors = map[color.Color]string{
+	color.FgRed:          "#cf222e",
+	color.FgGreen:        "#1a7f37",
+	color.FgYellow:       "#9a6700",
+	color.FgLightCyan:    "#0969da",
+	color.FgLightMagenta: "#8250df",
+	color.FgLightYellow:  "#953800",
+	color.FgLightGreen:   "#2da44e",
+}
+
+type htmlReport struct {
+	BreakingOnly bool
+	CRDs         []*htmlCRD
+	BreakingCRDs int
+}
+
+type htmlCRD struct {
+	Name             string
+	Anchor           string
+	Added            *AddedCRD
+	General          []htmlGeneralChange
+	AddedVersions    []string
+	DeletedVersions  []string
+	Versions         []*htmlVersion
+	Findings         []Finding
+	Breaking         bool
+	OnlyDescriptions bool
+}
+
+type htmlVersion struct {
+	Name             string
+	Anchor           string
+	General          []htmlGeneralChange
+	PrinterColumns   []htmlRow
+	Schema           *htmlSchemaNode
+	Findings         []Finding
+	Breaking         bool
+	Level            checker.Level
+	OnlyDescriptions bool
+}
+
+type htmlGeneralChange struct {
+	Description string
+	Breaking    bool
+}
+
+// htmlRow is a single change, shown with its old and new value side by side.
+// Rows that are not breaking are hidden when only breaking changes are shown.
+type htmlRow struct {
+	Subject     string
+	Action      string
+	From        string
+	To          string
+	Breaking    bool
+	Description bool
+}
+
+// htmlSchemaNode is a single node in the schema tree of a version.
+type htmlSchemaNode struct {
+	Name string
+	Path string
+	// Status is either empty (for unchanged parents of changed nodes),
+	// or one of the SchemaChange actions.
+	Status   string
+	Rows     []htmlRow
+	Findings []Finding
+	Children []*htmlSchemaNode
+
+	// Breaking and OnlyDescriptions describe the entire subtree and are
+	// used to filter the tree.
+	Breaking         bool
+	OnlyDescriptions bool
+
+	children map[string]*htmlSchemaNode
+}
+
+// Level returns the highest level of all breaking changes at this node.
+func (n *htmlSchemaNode) Level() checker.Level {
+	var level checker.Level
+	for _, f := range n.Findings {
+		if f.Level > level {
+			level = f.Level
+		}
+	}
+
+	return level
+}
+
+// Expandable returns true if the node has anything to show besides its name.
+func (n *htmlSchemaNode) Expandable() bool {
+	return len(n.Rows) > 0 || len(n.Findings) > 0 || len(n.Children) > 0
+}
+
+// RenderHTML renders the report as a single, self-contained HTML page with
+// a navigable list of all CRDs and versions and a collapsible schema tree
+// per version. If breakingOnly is true, the page initially only shows
+// breaking changes, but all changes are still included.
+func (r *Report) RenderHTML(breakingOnly bool) ([]byte, error) {
+	data := htmlReport{
+		BreakingOnly: breakingOnly,
+	}
+
+	anchors := newHTMLAnchors()
+
+	findings := map[string][]Finding{}
+	for _, f := range r.Findings() {
+		findings[f.CRD] = append(findings[f.CRD], f)
+	}
+
+	for _, crdIdentifier := range sets.List(sets.KeySet(r.Diffs).Union(sets.KeySet(r.AddedCRDs))) {
+		crd := &htmlCRD{
+			Name:   crdIdentifier,
+			Anchor: anchors.get(crdIdentifier),
+		}
+
+		if addedCRD, exists := r.AddedCRDs[crdIdentifier]; exists {
+			crd.Added = &addedCRD
+		} else {
+			crdDiff := r.Diffs[crdIdentifier]
+			buildHTMLCRD(crd, &crdDiff, findings[crdIdentifier], anchors)
+		}
+
+		if crd.Breaking {
+			data.BreakingCRDs++
+		}
+
+		data.CRDs = append(data.CRDs, crd)
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func buildHTMLCRD(crd *htmlCRD, crdDiff *compare.CRDDiff, findings []Finding, anchors *htmlAnchors) {
+	crd.General = htmlGeneralChanges(crdDiff.General)
+	crd.AddedVersions = crdDiff.AddedVersions
+	crd.DeletedVersions = crdDiff.DeletedVersions
+	crd.Breaking = crdDiff.HasBreakingChanges()
+	crd.OnlyDescriptions = len(crd.General) == 0 && len(crd.AddedVersions) == 0 && len(crd.DeletedVersions) == 0
+
+	deletedVersions := sets.New(crdDiff.DeletedVersions...)
+	versionFindings := map[string][]Finding{}
+
+	for _, f := range findings {
+		// removed versions cannot be shown in a version's schema tree
+		if f.Version == "" || deletedVersions.Has(f.Version) {
+			crd.Findings = append(crd.Findings, f)
+		} else {
+			versionFindings[f.Version] = append(versionFindings[f.Version], f)
+		}
+	}
+
+	for _, versionName := range sets.List(sets.KeySet(crdDiff.ChangedVersions)) {
+		versionDiff := crdDiff.ChangedVersions[versionName]
+		if !versionDiff.HasChanges() && len(versionFindings[versionName]) == 0 {
+			continue
+		}
+
+		version := &htmlVersion{
+			Name:           versionName,
+			Anchor:         anchors.get(crd.Name + "-" + versionName),
+			General:        htmlGeneralChanges(versionDiff.General),
+			PrinterColumns: htmlPrinterColumnRows(versionDiff.PrinterColumns),
+			Schema:         buildHTMLSchemaTree(&versionDiff, versionFindings[versionName]),
+			Findings:       versionFindings[versionName],
+			Breaking:       versionDiff.HasBreakingChanges(),
+			Level:          versionDiff.HighestLevel(),
+		}
+
+		version.OnlyDescriptions = len(version.General) == 0 && onlyDescriptions(version.PrinterColumns) && version.Schema.OnlyDescriptions
+		crd.OnlyDescriptions = crd.OnlyDescriptions && version.OnlyDescriptions
+
+		crd.Versions = append(crd.Versions, version)
+	}
+}
+
+func htmlGeneralChanges(changes []compare.Change) []htmlGeneralChange {
+	result := []htmlGeneralChange{}
+	for _, change := range changes {
+		result = append(result, htmlGeneralChange{
+			Description: capitalize(change.Description) + ".",
+			Breaking:    change.Breaking,
+		})
+	}
+
+	return result
+}
+
+func htmlPrinterColumnRows(d *compare.PrinterColumnsDiff) []htmlRow {
+	rows := []htmlRow{}
+	if d == nil {
+		return rows
+	}
+
+	for _, column := range d.Added {
+		rows = append(rows, htmlRow{Subject: column.Name, Action: ActionAdded, To: column.JSONPath})
+	}
+
+	for _, column := range d.Removed {
+		rows = append(rows, htmlRow{Subject: column.Name, Action: ActionRemoved, From: column.JSONPath})
+	}
+
+	for _, change := range d.Modified {
+		b, r := change.Base, change.Revision
+		for _, attr := range []struct {
+			name     string
+			from, to interface{}
+		}{
+			{"type", b.Type, r.Type},
+			{"format", b.Format, r.Format},
+			{"JSONPath", b.JSONPath, r.JSONPath},
+			{"priority", b.Priority, r.Priority},
+			{"description", b.Description, r.Description},
+		} {
+			if attr.from != attr.to {
+				rows = append(rows, htmlRow{
+					Subject:     fmt.Sprintf("%s %s", change.Name, attr.name),
+					Action:      valueAction(attr.from, attr.to),
+					From:        formatValue(attr.from),
+					To:          formatValue(attr.to),
+					Description: attr.name == "description",
+				})
+			}
+		}
+	}
+
+	for _, column := range d.Broken {
+		rows = append(rows, htmlRow{Subject: column.Name, Action: "broken", To: column.JSONPath, Breaking: true})
+	}
+
+	return rows
+}
+
+// buildHTMLSchemaTree turns the flat list of schema changes into a tree.
+// Added and removed properties become nodes of their own and breaking
+// changes are attached to the node they refer to.
+func buildHTMLSchemaTree(versionDiff *compare.CRDVersionDiff, findings []Finding) *htmlSchemaNode {
+	root := &htmlSchemaNode{Name: ".", Path: "."}
+
+	for _, path := range sets.List(sets.KeySet(versionDiff.SchemaChanges)) {
+		schemaDiff := versionDiff.SchemaChanges[path]
+
+		for _, change := range CollectSchemaChanges(path, &schemaDiff) {
+			node := root.ensure(change.Path)
+
+			if change.Attribute == "property" && change.Subschema == "" {
+				node.Status = change.Action
+				continue
+			}
+
+			for _, row := range htmlSchemaRows(change) {
+				if change.Subschema != "" {
+					row.Subject = fmt.Sprintf("%s: %s", change.Subschema, row.Subject)
+				}
+
+				node.Rows = append(node.Rows, row)
+			}
+
+			if node.Status == "" {
+				node.Status = ActionChanged
+			}
+		}
+	}
+
+	for _, f := range findings {
+		if f.Path != "" {
+			node := root.ensure(f.Path)
+			node.Findings = append(node.Findings, f)
+		}
+	}
+
+	root.finalize()
+
+	return root
+}
+
+// htmlSchemaLabels are human readable names for schema attributes that
+// are not plain values.
+var htmlSchemaLabels = map[string]string{
+	"":                         "schema",
+	"circularRef":              "circular reference",
+	"enum.value":               "enum value",
+	"required":                 "required property",
+	"anyOf":                    "anyOf subschema",
+	"oneOf":                    "oneOf subschema",
+	"allOf":                    "allOf subschema",
+	"listType":                 "list type",
+	"listMapKeys":              "list map keys",
+	"validationRule":           "validation rule",
+	"preserveUnknownFields":    "preserve unknown fields",
+	"prunedProperty":           "pruned property",
+	"embeddedResource":         "embedded resource",
+	"mapType":                  "map type",
+	"externalDocs":             "external docs",
+	"externalDocs.description": "external docs description",
+	"externalDocs.url":         "external docs URL",
+}
+
+func htmlSchemaLabel(attribute string) string {
+	if label, exists := htmlSchemaLabels[attribute]; exists {
+		return label
+	}
+
+	for _, attr := range SchemaValueAttributes {
+		if attr.Name == attribute {
+			return attr.Label
+		}
+	}
+
+	return attribute
+}
+
+// htmlSchemaRows returns the rows for a single schema change. Changes to
+// structured attributes like validation rules are shown as one row per
+// changed field.
+func htmlSchemaRows(change SchemaChange) []htmlRow {
+	label := htmlSchemaLabel(change.Attribute)
+
+	switch v := change.New.(type) {
+	case *diff.ExtensionsDiff:
+		return htmlInterfaceMapRows("extension", (*diff.InterfaceMapDiff)(v))
+
+	case *diff.DiscriminatorDiff:
+		rows := htmlAddedOrRemovedRows("discriminator", v.Added, v.Deleted)
+		rows = appendHTMLValueRow(rows, "discriminator property name", v.PropertyNameDiff)
+		if md := v.MappingDiff; md != nil {
+			for _, key := range sortedStrings(md.Added) {
+				rows = append(rows, htmlRow{Subject: "discriminator mapping " + key, Action: ActionAdded})
+			}
+			for _, key := range sortedStrings(md.Deleted) {
+				rows = append(rows, htmlRow{Subject: "discriminator mapping " + key, Action: ActionRemoved})
+			}
+			for _, key := range sets.List(sets.KeySet(md.Modified)) {
+				rows = appendHTMLValueRow(rows, "discriminator mapping "+key, md.Modified[key])
+			}
+		}
+		if v.ExtensionsDiff != nil {
+			rows = append(rows, htmlInterfaceMapRows("discriminator extension", (*diff.InterfaceMapDiff)(v.ExtensionsDiff))...)
+		}
+		return rows
+	}
+
+	switch change.Attribute {
+	case "listMapKeys":
+		from, _ := change.Old.([]string)
+		to, _ := change.New.([]string)
+
+		return []htmlRow{{Subject: label, Action: valueAction(joinList(from), joinList(to)), From: joinList(from), To: joinList(to)}}
+
+	case "validationRule":
+		from, _ := change.Old.(compare.ValidationRule)
+		to, _ := change.New.(compare.ValidationRule)
+		rows := []htmlRow{{Subject: label, Action: change.Action, From: from.Rule, To: to.Rule}}
+
+		if change.Action == ActionChanged {
+			for _, attr := range []struct {
+				name     string
+				from, to interface{}
+			}{
+				{"message", from.Message, to.Message},
+				{"message expression", from.MessageExpression, to.MessageExpression},
+				{"reason", from.Reason, to.Reason},
+				{"field path", from.FieldPath, to.FieldPath},
+				{"optionalOldSelf", from.OptionalOldSelf, to.OptionalOldSelf},
+			} {
+				if attr.from != attr.to {
+					rows = appendHTMLValueRow(rows, label+" "+attr.name, &diff.ValueDiff{From: attr.from, To: attr.to})
+				}
+			}
+		}
+
+		return rows
+	}
+
+	action := change.Action
+	switch {
+	case change.Attribute == "prunedProperty":
+		action = ActionAdded
+	case action == "":
+		action = valueAction(change.Old, change.New)
+	}
+
+	return []htmlRow{{
+		Subject:     label,
+		Action:      action,
+		From:        formatValue(change.Old),
+		To:          formatValue(change.New),
+		Description: change.Attribute == "description",
+	}}
+}
+
+func appendHTMLValueRow(rows []htmlRow, subject string, vd *diff.ValueDiff) []htmlRow {
+	if vd == nil {
+		return rows
+	}
+
+	return append(rows, htmlRow{
+		Subject: subject,
+		Action:  valueAction(vd.From, vd.To),
+		From:    formatValue(vd.From),
+		To:      formatValue(vd.To),
+	})
+}
+
+func htmlAddedOrRemovedRows(subject string, added, removed bool) []htmlRow {
+	rows := []htmlRow{}
+
+	if added {
+		rows = append(rows, htmlRow{Subject: subject, Action: ActionAdded})
+	}
+
+	if removed {
+		rows = append(rows, htmlRow{Subject: subject, Action: ActionRemoved})
+	}
+
+	return rows
+}
+
+func htmlInterfaceMapRows(subject string, d *diff.InterfaceMapDiff) []htmlRow {
+	rows := []htmlRow{}
+
+	for _, name := range sortedStrings(d.Added) {
+		rows = append(rows, htmlRow{Subject: subject + " " + name, Action: ActionAdded})
+	}
+
+	for _, name := range sortedStrings(d.Deleted) {
+		rows = append(rows, htmlRow{Subject: subject + " " + name, Action: ActionRemoved})
+	}
+
+	for _, name := range sets.List(sets.KeySet(d.Modified)) {
+		rows = appendHTMLValueRow(rows, subject+" "+name, d.Modified[name])
+	}
+
+	return rows
+}
+
+// ensure returns the node at the given path below n, creating all nodes
+// along the way. oasdiff's "items" is treated like "[]", unless a property
+// with that name exists.
+func (n *htmlSchemaNode) ensure(path string) *htmlSchemaNode {
+	current := n
+
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		if segment == "" {
+			continue
+		}
+
+		if current.children == nil {
+			current.children = map[string]*htmlSchemaNode{}
+		}
+
+		if _, exists := current.children[segment]; !exists && segment == "items" {
+			if _, exists := current.children["[]"]; exists {
+				segment = "[]"
+			}
+		}
+
+		child, exists := current.children[segment]
+		if !exists {
+			child = &htmlSchemaNode{
+				Name: segment,
+				Path: crd.ChildPath(current.Path, segment),
+			}
+			current.children[segment] = child
+		}
+
+		current = child
+	}
+
+	return current
+}
+
+// finalize sorts all children and determines the filter flags.
+func (n *htmlSchemaNode) finalize() {
+	n.Breaking = len(n.Findings) > 0
+	n.OnlyDescriptions = len(n.Findings) == 0 && (n.Status == "" || n.Status == ActionChanged) && onlyDescriptions(n.Rows)
+
+	// keep the context of breaking changes visible
+	for i := range n.Rows {
+		n.Rows[i].Breaking = n.Breaking
+	}
+
+	n.Children = nil
+	for _, name := range sets.List(sets.KeySet(n.children)) {
+		child := n.children[name]
+		child.finalize()
+
+		n.Breaking = n.Breaking || child.Breaking
+		n.OnlyDescriptions = n.OnlyDescriptions && child.OnlyDescriptions
+		n.Children = append(n.Children, child)
+	}
+}
+
+func onlyDescriptions(rows []htmlRow) bool {
+	for _, row := range rows {
+		if !row.Description {
+			return false
+		}
+	}
+
+	return true
+}
+
+var htmlAnchorInvalid = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+func htmlAnchor(s string) string {
+	return strings.Trim(htmlAnchorInvalid.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// htmlAnchors hands out unique anchors, as different names can map to the
+// same anchor (e.g. "a.b/Thing" and "a-b/Thing").
+type htmlAnchors struct {
+	used sets.Set[string]
+}
+
+func newHTMLAnchors() *htmlAnchors {
+	return &htmlAnchors{used: sets.New[string]()}
+}
+
+func (a *htmlAnchors) get(s string) string {
+	base := htmlAnchor(s)
+
+	anchor := base
+	for i := 2; a.used.Has(anchor); i++ {
+		anchor = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	a.used.Insert(anchor)
+
+	return anchor
+}
+
+func actionClass(action string) string {
+	switch action {
+	case ActionAdded:
+		return "action-add"
+	case ActionRemoved, "broken":
+		return "action-remove"
+	default:
+		return "action-change"
+	}
+}
+
+// htmlStyles renders a CSS class for every style in pkg/colors, so that
+// the HTML report uses the same color coding as the terminal output.
+func htmlStyles() template.CSS {
+	var css strings.Builder
+
+	for _, name := range sets.List(sets.KeySet(colors.Styles)) {
+		for _, c := range colors.Styles[name] {
+			if hex, exists := htmlColors[c]; exists {
+				fmt.Fprintf(&css, ".%s { color: %s; }\n", name, hex)
+				break
+			}
+		}
+	}
+
+	return template.CSS(css.String())
+}
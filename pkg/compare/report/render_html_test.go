@@ -0,0 +1,246 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tufin/oasdiff/checker"
+	"github.com/tufin/oasdiff/diff"
+	"github.com/tufin/oasdiff/utils"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+)
+
+func TestRenderHTML(t *testing.T) {
+	output, err := testReport().RenderHTML(false)
+	if err != nil {
+		t.Fatalf("Failed to render report: %v", err)
+	}
+
+	html := string(output)
+
+	for _, expected := range []string{
+		"<body>",
+		"<p>4 changed CRD(s), 2 with breaking changes.</p>",
+		`<a href="#example-com-thing" class="crd">example.com/Thing</a>`,
+		`<section id="example-com-thing"`,
+		`id="example-com-thing-v1"`,
+		`id="example-com-thing-v1beta1"`,
+		`title=".spec.foo">foo</span> <span class="badge action-add">added</span>`,
+		"Maximum length of .spec.name was decreased to 15.",
+		"Version is no longer served.",
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected output to contain %q, but got\n\n%s", expected, html)
+		}
+	}
+
+	output, err = testReport().RenderHTML(true)
+	if err != nil {
+		t.Fatalf("Failed to render report: %v", err)
+	}
+
+	if !strings.Contains(string(output), `<body class="breaking-only">`) {
+		t.Error("Expected breaking-only report to hide compatible changes.")
+	}
+}
+
+func TestRenderHTMLUniqueAnchors(t *testing.T) {
+	r := &Report{
+		Diffs: map[string]compare.CRDDiff{
+			"a.b/Thing": {AddedVersions: utils.StringList{"v1"}},
+			"a-b/Thing": {AddedVersions: utils.StringList{"v1"}},
+		},
+	}
+
+	output, err := r.RenderHTML(false)
+	if err != nil {
+		t.Fatalf("Failed to render report: %v", err)
+	}
+
+	html := string(output)
+
+	// CRDs are sorted, so a-b/Thing comes first
+	for _, expected := range []string{
+		`<a href="#a-b-thing" class="crd">a-b/Thing</a>`,
+		`<a href="#a-b-thing-2" class="crd">a.b/Thing</a>`,
+		`<section id="a-b-thing"`,
+		`<section id="a-b-thing-2"`,
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected output to contain %q, but got\n\n%s", expected, html)
+		}
+	}
+}
+
+func TestHTMLAnchors(t *testing.T) {
+	anchors := newHTMLAnchors()
+
+	for _, tc := range []struct {
+		name     string
+		expected string
+	}{
+		{name: "a.b/Thing", expected: "a-b-thing"},
+		{name: "a-b/Thing", expected: "a-b-thing-2"},
+		{name: "a-b-thing-2", expected: "a-b-thing-2-2"},
+		{name: "A_B/thing", expected: "a-b-thing-3"},
+		{name: "other", expected: "other"},
+	} {
+		if anchor := anchors.get(tc.name); anchor != tc.expected {
+			t.Errorf("Expected anchor %q for %q, but got %q.", tc.expected, tc.name, anchor)
+		}
+	}
+}
+
+func TestBuildHTMLSchemaTree(t *testing.T) {
+	versionDiff := &compare.CRDVersionDiff{
+		SchemaChanges: map[string]compare.CRDSchemaDiff{
+			".spec": {
+				AddedProperties:   utils.StringList{"foo"},
+				DeletedProperties: utils.StringList{"bar"},
+				Diff: &diff.SchemaDiff{
+					RequiredDiff: &diff.RequiredPropertiesDiff{StringsDiff: diff.StringsDiff{Added: utils.StringList{"foo"}}},
+					AnyOfDiff: &diff.SchemaListDiff{
+						Modified: diff.ModifiedSchemas{
+							"#1": &diff.SchemaDiff{MaxLengthDiff: &diff.ValueDiff{From: uint64(10), To: uint64(5)}},
+						},
+					},
+				},
+				ListMapKeys: &compare.StringListChange{From: []string{"a"}, To: []string{"a", "b"}},
+				ValidationRules: &compare.ValidationRulesDiff{
+					Modified: []compare.ValidationRuleChange{{
+						From: compare.ValidationRule{Rule: "self.x > 0", Message: "old"},
+						To:   compare.ValidationRule{Rule: "self.x > 0", Message: "new"},
+					}},
+				},
+			},
+			".spec.name": {
+				Diff: &diff.SchemaDiff{DescriptionDiff: &diff.ValueDiff{From: "old", To: "new"}},
+			},
+			".spec.extra": {
+				PrunedProperties: utils.StringList{"labels"},
+			},
+		},
+	}
+
+	findings := []Finding{{Path: ".spec.name", Level: checker.ERR, Message: "name changed"}}
+
+	root := buildHTMLSchemaTree(versionDiff, findings)
+
+	if len(root.Children) != 1 || root.Children[0].Path != ".spec" {
+		t.Fatalf("Expected root to only contain .spec, but got %+v.", root.Children)
+	}
+
+	spec := root.Children[0]
+	if spec.Status != ActionChanged {
+		t.Errorf("Expected .spec to be changed, but got %q.", spec.Status)
+	}
+
+	if !spec.Breaking || spec.OnlyDescriptions {
+		t.Errorf("Expected .spec to contain breaking changes, but got breaking=%v onlyDescriptions=%v.", spec.Breaking, spec.OnlyDescriptions)
+	}
+
+	assertRows(t, spec, []htmlRow{
+		{Subject: "anyOf/#1: maximum allowed length", Action: ActionChanged, From: "10", To: "5"},
+		{Subject: "list map keys", Action: ActionChanged, From: "[a]", To: "[a, b]"},
+		{Subject: "validation rule", Action: ActionChanged, From: "self.x > 0", To: "self.x > 0"},
+		{Subject: "validation rule message", Action: ActionChanged, From: "old", To: "new"},
+	})
+
+	children := map[string]*htmlSchemaNode{}
+	names := []string{}
+	for _, child := range spec.Children {
+		children[child.Name] = child
+		names = append(names, child.Name)
+	}
+
+	if expected := "bar,extra,foo,name"; strings.Join(names, ",") != expected {
+		t.Fatalf("Expected children %s, but got %s.", expected, strings.Join(names, ","))
+	}
+
+	if status := children["bar"].Status; status != ActionRemoved {
+		t.Errorf("Expected .spec.bar to be removed, but got %q.", status)
+	}
+
+	foo := children["foo"]
+	if foo.Status != ActionAdded {
+		t.Errorf("Expected .spec.foo to be added, but got %q.", foo.Status)
+	}
+	assertRows(t, foo, []htmlRow{{Subject: "required property", Action: ActionAdded, To: "foo"}})
+
+	// pruned properties are shown at the property itself
+	extra := children["extra"]
+	if extra.Status != "" || len(extra.Rows) > 0 {
+		t.Errorf("Expected .spec.extra to be unchanged, but got %q with %+v.", extra.Status, extra.Rows)
+	}
+	if len(extra.Children) != 1 {
+		t.Fatalf("Expected .spec.extra to contain the pruned property, but got %+v.", extra.Children)
+	}
+	assertRows(t, extra.Children[0], []htmlRow{{Subject: "pruned property", Action: ActionAdded, To: "labels"}})
+
+	name := children["name"]
+	if !name.Breaking || name.Level() != checker.ERR || len(name.Findings) != 1 {
+		t.Errorf("Expected .spec.name to carry the finding, but got %+v.", name.Findings)
+	}
+	if name.OnlyDescriptions {
+		t.Error("Expected breaking .spec.name to not be hidden as a description-only change.")
+	}
+	assertRows(t, name, []htmlRow{{Subject: "description", Action: ActionChanged, From: "old", To: "new", Breaking: true, Description: true}})
+}
+
+func TestBuildHTMLSchemaTreeOnlyDescriptions(t *testing.T) {
+	versionDiff := &compare.CRDVersionDiff{
+		SchemaChanges: map[string]compare.CRDSchemaDiff{
+			".spec.name": {
+				Diff: &diff.SchemaDiff{DescriptionDiff: &diff.ValueDiff{From: "", To: "new"}},
+			},
+		},
+	}
+
+	root := buildHTMLSchemaTree(versionDiff, nil)
+	if root.Breaking || !root.OnlyDescriptions {
+		t.Errorf("Expected description-only tree, but got breaking=%v onlyDescriptions=%v.", root.Breaking, root.OnlyDescriptions)
+	}
+
+	name := root.ensure(".spec.name")
+	assertRows(t, name, []htmlRow{{Subject: "description", Action: actionSet, To: "new", Description: true}})
+}
+
+func TestBuildHTMLSchemaTreeExternalDocs(t *testing.T) {
+	versionDiff := &compare.CRDVersionDiff{
+		SchemaChanges: map[string]compare.CRDSchemaDiff{
+			".spec": {
+				Diff: &diff.SchemaDiff{
+					ExternalDocsDiff: &diff.ExternalDocsDiff{
+						DescriptionDiff: &diff.ValueDiff{From: "", To: "docs"},
+						URLDiff:         &diff.ValueDiff{From: "https://a", To: "https://b"},
+					},
+				},
+			},
+		},
+	}
+
+	root := buildHTMLSchemaTree(versionDiff, nil)
+
+	assertRows(t, root.ensure(".spec"), []htmlRow{
+		{Subject: "external docs description", Action: actionSet, To: "docs"},
+		{Subject: "external docs URL", Action: ActionChanged, From: "https://a", To: "https://b"},
+	})
+}
+
+func assertRows(t *testing.T, node *htmlSchemaNode, expected []htmlRow) {
+	t.Helper()
+
+	if len(node.Rows) != len(expected) {
+		t.Fatalf("Expected %d rows at %s, but got %+v.", len(expected), node.Path, node.Rows)
+	}
+
+	for i, row := range node.Rows {
+		if row != expected[i] {
+			t.Errorf("Expected row %d at %s to be %+v, but got %+v.", i, node.Path, expected[i], row)
+		}
+	}
+}
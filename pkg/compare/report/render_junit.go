@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/tufin/oasdiff/checker"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// junitCRDTestCase is the name of the test case for changes that affect
+// the entire CRD instead of a single version.
+const junitCRDTestCase = "crd"
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message  string `xml:"message,attr"`
+	Type     string `xml:"type,attr"`
+	Contents string `xml:",chardata"`
+}
+
+// RenderJUnit renders the report as a JUnit XML test report. Every CRD is
+// a test suite and every version a test case, which fails if the version
+// contains breaking changes.
+func (r *Report) RenderJUnit() ([]byte, error) {
+	findings := map[string]map[string][]Finding{}
+	for _, f := range r.Findings() {
+		if findings[f.CRD] == nil {
+			findings[f.CRD] = map[string][]Finding{}
+		}
+		findings[f.CRD][f.Version] = append(findings[f.CRD][f.Version], f)
+	}
+
+	root := junitTestSuites{
+		Name: "crdiff",
+	}
+
+	for _, crdIdentifier := range sets.List(sets.KeySet(r.Diffs).Union(sets.KeySet(r.AddedCRDs))) {
+		versions := sets.New[string]()
+
+		if addedCRD, exists := r.AddedCRDs[crdIdentifier]; exists {
+			versions.Insert(addedCRD.Versions...)
+		} else {
+			crdDiff := r.Diffs[crdIdentifier]
+			versions.Insert(crdDiff.AddedVersions...)
+			versions.Insert(crdDiff.DeletedVersions...)
+			versions.Insert(sets.List(sets.KeySet(crdDiff.ChangedVersions))...)
+		}
+
+		suite := junitTestSuite{
+			Name: crdIdentifier,
+		}
+
+		// changes to the CRD itself come first, then all versions
+		for _, version := range append([]string{""}, sets.List(versions)...) {
+			name := version
+			if name == "" {
+				name = junitCRDTestCase
+			}
+
+			testCase := junitTestCase{
+				Name:      name,
+				ClassName: crdIdentifier,
+				Failure:   junitFailureOf(findings[crdIdentifier][version]),
+			}
+
+			suite.TestCases = append(suite.TestCases, testCase)
+			suite.Tests++
+
+			if testCase.Failure != nil {
+				suite.Failures++
+			}
+		}
+
+		root.Suites = append(root.Suites, suite)
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+	}
+
+	encoded, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(xml.Header + string(encoded) + "\n"), nil
+}
+
+func junitFailureOf(findings []Finding) *junitFailure {
+	if len(findings) == 0 {
+		return nil
+	}
+
+	highest := checker.INFO
+	lines := []string{}
+
+	for _, f := range findings {
+		if f.Level > highest {
+			highest = f.Level
+		}
+
+		lines = append(lines, fmt.Sprintf("[%s] %s: %s", levelName(f.Level), f.RuleID, f.Message))
+	}
+
+	message := "1 breaking change"
+	if len(findings) > 1 {
+		message = fmt.Sprintf("%d breaking changes", len(findings))
+	}
+
+	return &junitFailure{
+		Message:  message,
+		Type:     levelName(highest),
+		Contents: strings.Join(lines, "\n"),
+	}
+}
+
+func levelName(level checker.Level) string {
+	switch {
+	case level >= checker.ERR:
+		return "error"
+	case level == checker.WARN:
+		return "warning"
+	default:
+		return "info"
+	}
+}
@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestRenderJUnit(t *testing.T) {
+	output, err := testReport().RenderJUnit()
+	if err != nil {
+		t.Fatalf("Failed to render JUnit report: %v", err)
+	}
+
+	compareGoldenFile(t, "testdata/report.junit.xml", output)
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal(output, &suites); err != nil {
+		t.Fatalf("Failed to decode JUnit report: %v", err)
+	}
+
+	// added and compatible CRDs are passing test suites
+	if suites.Tests != 10 || suites.Failures != 3 || len(suites.Suites) != 4 {
+		t.Errorf("Expected 4 suites with 10 tests and 3 failures, but got %d suites with %d tests and %d failures.", len(suites.Suites), suites.Tests, suites.Failures)
+	}
+
+	for _, suite := range suites.Suites {
+		failures := 0
+		for _, testCase := range suite.TestCases {
+			if testCase.Failure != nil {
+				failures++
+			}
+		}
+
+		if suite.Tests != len(suite.TestCases) || suite.Failures != failures {
+			t.Errorf("Suite %s claims %d tests and %d failures, but contains %d tests and %d failures.", suite.Name, suite.Tests, suite.Failures, len(suite.TestCases), failures)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tufin/oasdiff/checker"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+	"go.xrstf.de/crdiff/pkg/indent"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// RenderMarkdown renders the report as GitHub/GitLab-flavored markdown,
+// suitable for pull request comments.
+func (r *Report) RenderMarkdown(breakingOnly bool) string {
+	// the text renderers are reused for individual changes, which
+	// must not contain any ANSI color codes
+	defer disableColors()()
+
+	// the summary table must only count breaking changes as well
+	if breakingOnly {
+		r = r.OnlyBreaking()
+	}
+
+	var (
+		summary  strings.Builder
+		breaking strings.Builder
+		changes  strings.Builder
+	)
+
+	summary.WriteString("| CRD | Added Versions | Removed Versions | Changed Versions | Breaking Changes |\n")
+	summary.WriteString("|-----|----------------|------------------|------------------|------------------|\n")
+
+	crdCount := 0
+
+	for _, crdIdentifier := range sets.List(sets.KeySet(r.Diffs).Union(sets.KeySet(r.AddedCRDs))) {
+		if addedCRD, exists := r.AddedCRDs[crdIdentifier]; exists {
+			// added CRDs are never breaking
+			if breakingOnly {
+				continue
+			}
+
+			fmt.Fprintf(&summary, "| %s | %s | – | – | 0 |\n", code(crdIdentifier), codeList(addedCRD.Versions))
+
+			fmt.Fprintf(&changes, "### %s\n\n", code(crdIdentifier))
+			fmt.Fprintf(&changes, "Added CRD with %s scope and versions %s.\n\n", code(addedCRD.Scope), codeList(addedCRD.Versions))
+
+			crdCount++
+			continue
+		}
+
+		crdDiff := r.Diffs[crdIdentifier]
+		if !shouldPrintCRD(&crdDiff, breakingOnly) {
+			continue
+		}
+
+		breakingChanges := collectMarkdownBreakingChanges(&crdDiff)
+
+		fmt.Fprintf(
+			&summary,
+			"| %s | %s | %s | %s | %d |\n",
+			code(crdIdentifier),
+			codeList(crdDiff.AddedVersions),
+			codeList(crdDiff.DeletedVersions),
+			codeList(sets.List(sets.KeySet(crdDiff.ChangedVersions))),
+			len(breakingChanges),
+		)
+
+		if len(breakingChanges) > 0 {
+			fmt.Fprintf(&breaking, "### %s\n\n", code(crdIdentifier))
+			for _, line := range breakingChanges {
+				fmt.Fprintf(&breaking, "- %s\n", line)
+			}
+			breaking.WriteString("\n")
+		}
+
+		if !breakingOnly {
+			fmt.Fprintf(&changes, "### %s\n\n", code(crdIdentifier))
+			renderCRDDiffAsMarkdown(&crdDiff, &changes)
+		}
+
+		crdCount++
+	}
+
+	var out strings.Builder
+
+	out.WriteString("## CRD Changes\n\n")
+
+	if crdCount == 0 {
+		if breakingOnly {
+			out.WriteString("No breaking changes detected.\n")
+		} else {
+			out.WriteString("No changes detected.\n")
+		}
+
+		return out.String()
+	}
+
+	out.WriteString(summary.String())
+	out.WriteString("\n")
+
+	if breaking.Len() > 0 {
+		out.WriteString("## :warning: Breaking Changes\n\n")
+		out.WriteString(breaking.String())
+	}
+
+	if changes.Len() > 0 {
+		out.WriteString("## All Changes\n\n")
+		out.WriteString(changes.String())
+	}
+
+	return strings.TrimSpace(out.String()) + "\n"
+}
+
+// collectMarkdownBreakingChanges returns one list item per breaking change.
+func collectMarkdownBreakingChanges(crdDiff *compare.CRDDiff) []string {
+	items := []string{}
+
+	for _, change := range crdDiff.General {
+		if change.Breaking {
+			items = append(items, fmt.Sprintf("%s %s", levelBadge(change.BreakingLevel()), escapeMarkdown(change.Description)))
+		}
+	}
+
+	for _, version := range crdDiff.DeletedVersions {
+		items = append(items, fmt.Sprintf("%s Version %s was removed.", levelBadge(checker.ERR), code(version)))
+	}
+
+	for _, version := range sets.List(sets.KeySet(crdDiff.ChangedVersions)) {
+		versionDiff := crdDiff.ChangedVersions[version]
+
+		for _, change := range versionDiff.General {
+			if change.Breaking {
+				items = append(items, fmt.Sprintf("%s %s: %s", levelBadge(change.BreakingLevel()), code(version), escapeMarkdown(change.Description)))
+			}
+		}
+
+		if columns := versionDiff.PrinterColumns; columns != nil {
+			for _, column := range columns.Broken {
+				items = append(items, fmt.Sprintf(
+					"%s %s: Printer column %s points to %s, which does not exist in the schema.",
+					levelBadge(checker.WARN),
+					code(version),
+					code(column.Name),
+					code(column.JSONPath),
+				))
+			}
+		}
+
+		for _, bc := range versionDiff.BreakingChanges {
+			items = append(items, fmt.Sprintf("%s %s: %s", levelBadge(bc.Level), code(version), escapeMarkdown(stripChangeMarker(renderBreakingChange(bc)))))
+		}
+	}
+
+	return items
+}
+
+func renderCRDDiffAsMarkdown(crdDiff *compare.CRDDiff, out *strings.Builder) {
+	for _, change := range crdDiff.General {
+		fmt.Fprintf(out, "- %s\n", escapeMarkdown(change.Description))
+	}
+
+	for _, version := range crdDiff.AddedVersions {
+		fmt.Fprintf(out, "- Added version %s.\n", code(version))
+	}
+
+	for _, version := range crdDiff.DeletedVersions {
+		fmt.Fprintf(out, "- Removed version %s.\n", code(version))
+	}
+
+	if len(crdDiff.General) > 0 || len(crdDiff.AddedVersions) > 0 || len(crdDiff.DeletedVersions) > 0 {
+		out.WriteString("\n")
+	}
+
+	for _, version := range sets.List(sets.KeySet(crdDiff.ChangedVersions)) {
+		versionDiff := crdDiff.ChangedVersions[version]
+		if !versionDiff.HasChanges() {
+			continue
+		}
+
+		fmt.Fprintf(out, "<details>\n<summary>Version <code>%s</code></summary>\n\n", version)
+
+		for _, change := range versionDiff.General {
+			fmt.Fprintf(out, "- %s\n", escapeMarkdown(change.Description))
+		}
+
+		if len(versionDiff.General) > 0 {
+			out.WriteString("\n")
+		}
+
+		blocks := renderSchemaChangesAsText(&versionDiff)
+		if columns := renderPrinterColumnsDiffAsText(versionDiff.PrinterColumns, false); columns != nil {
+			blocks = append(blocks, columns)
+		}
+
+		if len(blocks) > 0 {
+			printer := indent.NewIndenter()
+			for i, block := range blocks {
+				if i > 0 {
+					printer.AddLine("")
+				}
+				printer.Add(block)
+			}
+
+			fmt.Fprintf(out, "%s\n\n", codeBlock(printer.String()))
+		}
+
+		out.WriteString("</details>\n\n")
+	}
+}
+
+func levelBadge(level checker.Level) string {
+	if level >= checker.ERR {
+		return ":x: **error**"
+	}
+
+	return ":warning: **warning**"
+}
+
+// stripChangeMarker removes the leading "+ ", "~ " etc. from a line
+// rendered for text output.
+func stripChangeMarker(s string) string {
+	if len(s) > 2 && s[1] == ' ' && strings.ContainsRune("+-~!", rune(s[0])) {
+		return s[2:]
+	}
+
+	return s
+}
+
+func code(s string) string {
+	delimiter := strings.Repeat("`", longestBacktickRun(s)+1)
+
+	// a space is required to separate backticks in the content from the delimiter
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+
+	return delimiter + s + delimiter
+}
+
+// codeBlock wraps s in a fenced code block. The fence is longer than any
+// run of backticks in s, so that the content cannot end the block early.
+func codeBlock(s string) string {
+	fence := strings.Repeat("`", max(3, longestBacktickRun(s)+1))
+
+	return fence + "\n" + s + "\n" + fence
+}
+
+func longestBacktickRun(s string) int {
+	longest, current := 0, 0
+
+	for _, r := range s {
+		if r == '`' {
+			current++
+			longest = max(longest, current)
+		} else {
+			current = 0
+		}
+	}
+
+	return longest
+}
+
+func codeList(items []string) string {
+	if len(items) == 0 {
+		return "–"
+	}
+
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = code(item)
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"|", `\|`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tufin/oasdiff/checker"
+	"github.com/tufin/oasdiff/diff"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	output := testReport().RenderMarkdown(false)
+
+	expectedSummary := strings.Join([]string{
+		"| CRD | Added Versions | Removed Versions | Changed Versions | Breaking Changes |",
+		"|-----|----------------|------------------|------------------|------------------|",
+		"| `example.com/Added` | `v1` | – | – | 0 |",
+		"| `example.com/Compatible` | `v2` | – | – | 0 |",
+		"| `example.com/Removed` | – | – | – | 1 |",
+		"| `example.com/Thing` | `v2` | `v1alpha1` | `v1`, `v1beta1` | 4 |",
+	}, "\n")
+
+	if !strings.HasPrefix(output, "## CRD Changes\n\n"+expectedSummary+"\n\n") {
+		t.Errorf("Expected output to start with the summary table\n\n%s\n\nbut got\n\n%s", expectedSummary, output)
+	}
+
+	expectedBreaking := strings.Join([]string{
+		"### `example.com/Thing`",
+		"",
+		"- :x: **error** Version `v1alpha1` was removed.",
+		"- :warning: **warning** `v1`: version is no longer served",
+		"- :warning: **warning** `v1`: Printer column `Ready` points to `.status.ready`, which does not exist in the schema.",
+		"- :x: **error** `v1`: Maximum length of .spec.name was decreased to 15.",
+	}, "\n")
+
+	for _, expected := range []string{
+		"## :warning: Breaking Changes\n\n### `example.com/Removed`\n\n- :x: **error** CRD has been removed\n",
+		expectedBreaking,
+		"### `example.com/Added`\n\nAdded CRD with `Namespaced` scope and versions `v1`.\n",
+		"<details>\n<summary>Version <code>v1beta1</code></summary>\n\n```\n.spec.name:\n  ~ changed description from old to new\n```\n\n</details>",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain\n\n%s\n\nbut got\n\n%s", expected, output)
+		}
+	}
+}
+
+func TestRenderMarkdownBreakingOnly(t *testing.T) {
+	output := testReport().RenderMarkdown(true)
+
+	expectedSummary := strings.Join([]string{
+		"| `example.com/Removed` | – | – | – | 1 |",
+		"| `example.com/Thing` | – | `v1alpha1` | `v1` | 4 |",
+	}, "\n")
+
+	if !strings.Contains(output, expectedSummary) {
+		t.Errorf("Expected summary table to only contain breaking changes\n\n%s\n\nbut got\n\n%s", expectedSummary, output)
+	}
+
+	for _, unexpected := range []string{"example.com/Added", "example.com/Compatible", "## All Changes", "<details>"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("Expected output not to contain %q, but got\n\n%s", unexpected, output)
+		}
+	}
+
+	if output := (&Report{}).RenderMarkdown(true); output != "## CRD Changes\n\nNo breaking changes detected.\n" {
+		t.Errorf("Unexpected output for an empty report:\n\n%s", output)
+	}
+}
+
+func TestRenderMarkdownEscaping(t *testing.T) {
+	r := &Report{
+		Diffs: map[string]compare.CRDDiff{
+			"example.com/Thing": {
+				General: []compare.Change{
+					{Breaking: true, Level: checker.ERR, Description: "scope changed from *Cluster* to <Namespaced> | [link](x)"},
+				},
+				ChangedVersions: map[string]compare.CRDVersionDiff{
+					"v1": {
+						SchemaChanges: map[string]compare.CRDSchemaDiff{
+							".spec": {Diff: &diff.SchemaDiff{DescriptionDiff: &diff.ValueDiff{From: "Use ```yaml blocks.", To: "Use ````yaml blocks."}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	output := r.RenderMarkdown(false)
+
+	escaped := `scope changed from \*Cluster\* to \<Namespaced\> \| \[link\](x)`
+	if !strings.Contains(output, "- :x: **error** "+escaped+"\n") {
+		t.Errorf("Expected description to be escaped as %q, but got\n\n%s", escaped, output)
+	}
+
+	// the fence must be longer than the longest run of backticks in the content
+	expectedBlock := "`````\n.spec:\n  ~ changed description from Use ```yaml blocks. to Use ````yaml blocks.\n`````"
+	if !strings.Contains(output, expectedBlock) {
+		t.Errorf("Expected output to contain\n\n%s\n\nbut got\n\n%s", expectedBlock, output)
+	}
+}
+
+func TestCode(t *testing.T) {
+	testcases := map[string]string{
+		"foo":     "`foo`",
+		"a`b":     "``a`b``",
+		"`a`":     "`` `a` ``",
+		"a``b`c":  "```a``b`c```",
+		"":        "``",
+		"foo bar": "`foo bar`",
+	}
+
+	for input, expected := range testcases {
+		if result := code(input); result != expected {
+			t.Errorf("Expected code(%q) to be %q, but got %q.", input, expected, result)
+		}
+	}
+}
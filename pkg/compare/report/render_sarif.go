@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/tufin/oasdiff/checker"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// RenderSARIF renders all breaking changes in the report as a SARIF 2.1.0
+// log, suitable for code scanning tools. Every check becomes a rule and
+// results point to the line in the revision (or, for removed CRDs, the
+// base) where the change was found.
+func (r *Report) RenderSARIF(toolVersion string) ([]byte, error) {
+	findings := r.Findings()
+
+	ruleIDs := sets.New[string]()
+	for _, f := range findings {
+		ruleIDs.Insert(f.RuleID)
+	}
+
+	rules := []sarifRule{}
+	ruleIndices := map[string]int{}
+
+	for i, ruleID := range sets.List(ruleIDs) {
+		rules = append(rules, sarifRule{
+			ID:               ruleID,
+			ShortDescription: sarifMessage{Text: describeRule(ruleID)},
+		})
+		ruleIndices[ruleID] = i
+	}
+
+	results := []sarifResult{}
+	for _, f := range findings {
+		results = append(results, sarifResult{
+			RuleID:    f.RuleID,
+			RuleIndex: ruleIndices[f.RuleID],
+			Level:     sarifLevel(f.Level),
+			Message:   sarifMessage{Text: fmt.Sprintf("%s: %s", findingScope(f), f.Message)},
+			Locations: []sarifLocation{sarifLocationOf(f)},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{
+				Driver: sarifDriver{
+					Name:           "crdiff",
+					InformationURI: "https://github.com/xrstf/crdiff",
+					Version:        toolVersion,
+					Rules:          rules,
+				},
+			},
+			Results: results,
+		}},
+	}
+
+	encoded, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(encoded, '\n'), nil
+}
+
+func sarifLevel(level checker.Level) string {
+	switch {
+	case level >= checker.ERR:
+		return "error"
+	case level == checker.WARN:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func sarifLocationOf(f Finding) sarifLocation {
+	location := sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{{
+			FullyQualifiedName: findingSubject(f),
+		}},
+	}
+
+	if f.Location == nil || f.Location.Filename == "" {
+		return location
+	}
+
+	physical := &sarifPhysicalLocation{
+		ArtifactLocation: artifactLocation(f.Location.Filename),
+	}
+
+	if f.Location.Line > 0 {
+		physical.Region = &sarifRegion{StartLine: f.Location.Line}
+	}
+
+	location.PhysicalLocation = physical
+
+	return location
+}
+
+// artifactLocation turns a filename into a SARIF artifact location,
+// relative to the source root whenever possible.
+func artifactLocation(filename string) sarifArtifactLocation {
+	filename = relativeFilename(filename)
+
+	if filepath.IsAbs(filename) {
+		return sarifArtifactLocation{
+			URI: "file://" + filename,
+		}
+	}
+
+	return sarifArtifactLocation{
+		URI:       filename,
+		URIBaseID: "%SRCROOT%",
+	}
+}
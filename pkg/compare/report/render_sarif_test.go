@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderSARIF(t *testing.T) {
+	encoded, err := testReport().RenderSARIF("v1.2.3")
+	if err != nil {
+		t.Fatalf("Failed to render SARIF: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(encoded, &log); err != nil {
+		t.Fatalf("Failed to decode SARIF: %v", err)
+	}
+
+	if log.Version != sarifVersion || len(log.Runs) != 1 {
+		t.Fatalf("Expected a single SARIF %s run, but got %s with %d runs.", sarifVersion, log.Version, len(log.Runs))
+	}
+
+	run := log.Runs[0]
+	if run.Tool.Driver.Version != "v1.2.3" {
+		t.Errorf("Expected tool version v1.2.3, but got %q.", run.Tool.Driver.Version)
+	}
+
+	expectedRules := []string{
+		crdChangedRuleID,
+		printerColumnBrokenRuleID,
+		"request-property-max-length-decreased",
+		versionChangedRuleID,
+		versionRemovedRuleID,
+	}
+
+	rules := run.Tool.Driver.Rules
+	if len(rules) != len(expectedRules) {
+		t.Fatalf("Expected %d rules, but got %+v.", len(expectedRules), rules)
+	}
+
+	for i, rule := range rules {
+		if rule.ID != expectedRules[i] {
+			t.Errorf("Expected rule #%d to be %q, but got %q.", i, expectedRules[i], rule.ID)
+		}
+
+		if rule.ShortDescription.Text == "" {
+			t.Errorf("Rule %q has no description.", rule.ID)
+		}
+	}
+
+	expectedResults := []struct {
+		ruleID string
+		level  string
+		uri    string
+		line   int
+	}{
+		{ruleID: crdChangedRuleID, level: "error", uri: "base/removed.yaml", line: 1},
+		{ruleID: versionRemovedRuleID, level: "error", uri: "crds/thing.yaml", line: 10},
+		{ruleID: versionChangedRuleID, level: "warning", uri: "crds/thing.yaml", line: 20},
+		{ruleID: printerColumnBrokenRuleID, level: "warning", uri: "crds/thing.yaml", line: 20},
+		{ruleID: "request-property-max-length-decreased", level: "error", uri: "crds/thing.yaml", line: 40},
+	}
+
+	if len(run.Results) != len(expectedResults) {
+		t.Fatalf("Expected %d results, but got %+v.", len(expectedResults), run.Results)
+	}
+
+	for i, result := range run.Results {
+		expected := expectedResults[i]
+
+		if result.RuleID != expected.ruleID || result.Level != expected.level {
+			t.Errorf("Expected result #%d to be a %s %q, but got a %s %q.", i, expected.level, expected.ruleID, result.Level, result.RuleID)
+		}
+
+		// the rule index must point to the rule of the result
+		if result.RuleIndex < 0 || result.RuleIndex >= len(rules) || rules[result.RuleIndex].ID != result.RuleID {
+			t.Errorf("Result #%d (%s) has wrong rule index %d.", i, result.RuleID, result.RuleIndex)
+		}
+
+		if len(result.Locations) != 1 || result.Locations[0].PhysicalLocation == nil {
+			t.Errorf("Expected result #%d to have a physical location, but got %+v.", i, result.Locations)
+			continue
+		}
+
+		physical := result.Locations[0].PhysicalLocation
+		if physical.ArtifactLocation.URI != expected.uri || physical.ArtifactLocation.URIBaseID != "%SRCROOT%" {
+			t.Errorf("Expected result #%d to point to %s, but got %+v.", i, expected.uri, physical.ArtifactLocation)
+		}
+
+		if physical.Region == nil || physical.Region.StartLine != expected.line {
+			t.Errorf("Expected result #%d to point to line %d, but got %+v.", i, expected.line, physical.Region)
+		}
+	}
+}
+
+func TestRenderSARIFWithoutSource(t *testing.T) {
+	r := testReport()
+	r.Sources = nil
+
+	encoded, err := r.RenderSARIF("")
+	if err != nil {
+		t.Fatalf("Failed to render SARIF: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(encoded, &log); err != nil {
+		t.Fatalf("Failed to decode SARIF: %v", err)
+	}
+
+	for _, result := range log.Runs[0].Results {
+		location := result.Locations[0]
+
+		if location.PhysicalLocation != nil {
+			t.Errorf("Expected no physical location for %s, but got %+v.", result.RuleID, location.PhysicalLocation)
+		}
+
+		if len(location.LogicalLocations) != 1 || location.LogicalLocations[0].FullyQualifiedName == "" {
+			t.Errorf("Expected a logical location for %s, but got %+v.", result.RuleID, location.LogicalLocations)
+		}
+	}
+}
+
+func TestRelativeFilename(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to determine working directory: %v", err)
+	}
+
+	parent := filepath.Dir(wd)
+
+	testcases := []struct {
+		filename string
+		expected string
+	}{
+		{filename: "crds/thing.yaml", expected: "crds/thing.yaml"},
+		{filename: "crds/../thing.yaml", expected: "thing.yaml"},
+		{filename: filepath.Join(wd, "crds", "thing.yaml"), expected: "crds/thing.yaml"},
+		// directories whose names start with two dots are not outside
+		{filename: filepath.Join(wd, "..crds", "thing.yaml"), expected: "..crds/thing.yaml"},
+		{filename: filepath.Join(parent, "thing.yaml"), expected: filepath.ToSlash(filepath.Join(parent, "thing.yaml"))},
+		{filename: parent, expected: filepath.ToSlash(parent)},
+	}
+
+	for _, tc := range testcases {
+		if result := relativeFilename(tc.filename); result != tc.expected {
+			t.Errorf("Expected %q to become %q, but got %q.", tc.filename, tc.expected, result)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"go.xrstf.de/crdiff/pkg/colors"
+	"go.xrstf.de/crdiff/pkg/compare"
+)
+
+// NewTemplate parses a user-supplied text/template and makes the crdiff
+// function library available to it.
+func NewTemplate(name string, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs(false)).Parse(text)
+}
+
+// RenderTemplate executes a template created with NewTemplate against the
+// report. Templates can call breakingOnly to find out if only breaking
+// changes should be rendered.
+func (r *Report) RenderTemplate(tpl *template.Template, breakingOnly bool) ([]byte, error) {
+	tpl, err := tpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Funcs(templateFuncs(breakingOnly)).Execute(&buf, r); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func templateFuncs(breakingOnly bool) template.FuncMap {
+	return template.FuncMap{
+		"breakingOnly": func() bool { return breakingOnly },
+
+		// colors
+		"style": templateStyle,
+
+		// paths
+		"normalizePath": normalizePath,
+		"displayPath":   displayPath,
+		"pathSegments":  pathSegments,
+
+		// breaking changes
+		"findings":        func(r *Report) []Finding { return r.Findings() },
+		"breakingMessage": func(bc compare.BreakingChange) string { return stripChangeMarker(renderBreakingChange(bc)) },
+		"describeRule":    describeRule,
+		"levelName":       levelName,
+
+		// sorting and lists
+		"sortedKeys": sortedKeys,
+		"sortStrings": func(items []string) []string {
+			sorted := append([]string{}, items...)
+			sort.Strings(sorted)
+			return sorted
+		},
+		"join": func(sep string, items []string) string { return strings.Join(items, sep) },
+	}
+}
+
+// templateStyle renders a value using one of the styles from pkg/colors,
+// e.g. {{ style "crd" .Name }}.
+func templateStyle(name string, value interface{}) (string, error) {
+	style, exists := colors.Styles[name]
+	if !exists {
+		return "", fmt.Errorf("unknown style %q", name)
+	}
+
+	return style.Render(value), nil
+}
+
+// normalizePath turns oasdiff's "items" path segments into "[]", like they
+// are used in schema changes.
+func normalizePath(path string) string {
+	segments := pathSegments(path)
+	for i, segment := range segments {
+		if segment == "items" {
+			segments[i] = "[]"
+		}
+	}
+
+	return "." + strings.Join(segments, ".")
+}
+
+// displayPath formats a schema path for humans, e.g. ".spec.ports.[].name"
+// becomes "spec.ports[].name".
+func displayPath(path string) string {
+	segments := pathSegments(path)
+	if len(segments) == 0 {
+		return "."
+	}
+
+	return strings.ReplaceAll(strings.Join(segments, "."), ".[]", "[]")
+}
+
+// pathSegments splits a schema path into its segments; the root path has
+// no segments.
+func pathSegments(path string) []string {
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return []string{}
+	}
+
+	return strings.Split(path, ".")
+}
+
+// sortedKeys returns the sorted keys of a map with string keys, so that
+// templates can iterate over the report in a stable order.
+func sortedKeys(m interface{}) ([]string, error) {
+	value := reflect.ValueOf(m)
+	if value.Kind() != reflect.Map || value.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("expected a map with string keys, got %T", m)
+	}
+
+	keys := []string{}
+	for _, key := range value.MapKeys() {
+		keys = append(keys, key.String())
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
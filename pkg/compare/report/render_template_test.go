@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gookit/color"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	// the example from the README, plus a check for breakingOnly
+	tpl, err := NewTemplate("slack", strings.Join([]string{
+		"{{- range $crd, $diff := .Diffs }}",
+		"*{{ $crd }}*",
+		"{{- range findings $ }}{{ if eq .CRD $crd }}",
+		"• [{{ levelName .Level }}] {{ .Version }} `{{ displayPath .Path }}`: {{ .Message }}",
+		"{{- end }}{{ end }}",
+		"{{- end }}",
+		"{{ if breakingOnly }}breaking only{{ end }}",
+	}, "\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+
+	output, err := testReport().RenderTemplate(tpl, true)
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		"",
+		"*example.com/Compatible*",
+		"*example.com/Removed*",
+		"• [error]  `.`: CRD has been removed.",
+		"*example.com/Thing*",
+		"• [error] v1alpha1 `.`: Version v1alpha1 was removed.",
+		"• [warning] v1 `.`: Version is no longer served.",
+		"• [warning] v1 `.`: Printer column Ready points to .status.ready, which does not exist in the schema.",
+		"• [error] v1 `spec.name`: Maximum length of .spec.name was decreased to 15.",
+		"breaking only",
+	}, "\n")
+
+	if string(output) != expected {
+		t.Errorf("Expected\n\n%s\n\nbut got\n\n%s", expected, output)
+	}
+
+	// the breakingOnly function must not leak into the parsed template
+	output, err = testReport().RenderTemplate(tpl, false)
+	if err != nil {
+		t.Fatalf("Failed to render template: %v", err)
+	}
+
+	if strings.Contains(string(output), "breaking only") {
+		t.Errorf("Expected breakingOnly to be false, but got\n\n%s", output)
+	}
+}
+
+func TestRenderTemplateError(t *testing.T) {
+	tpl, err := NewTemplate("invalid", `{{ style "unknown" "foo" }}`)
+	if err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+
+	if _, err := testReport().RenderTemplate(tpl, false); err == nil {
+		t.Fatal("Expected an unknown style to fail the template.")
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	testcases := map[string]string{
+		"":                       ".",
+		".":                      ".",
+		".spec":                  ".spec",
+		"spec.name":              ".spec.name",
+		".spec.ports.items.name": ".spec.ports.[].name",
+		".spec.ports.[].name":    ".spec.ports.[].name",
+		".items.items":           ".[].[]",
+	}
+
+	for path, expected := range testcases {
+		if normalized := normalizePath(path); normalized != expected {
+			t.Errorf("Expected %q to become %q, but got %q.", path, expected, normalized)
+		}
+	}
+}
+
+func TestDisplayPath(t *testing.T) {
+	testcases := map[string]string{
+		"":                    ".",
+		".":                   ".",
+		".spec":               "spec",
+		".spec.ports.[].name": "spec.ports[].name",
+		".spec.matrix.[].[]":  "spec.matrix[][]",
+		".[]":                 "[]",
+	}
+
+	for path, expected := range testcases {
+		if display := displayPath(path); display != expected {
+			t.Errorf("Expected %q to become %q, but got %q.", path, expected, display)
+		}
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	keys, err := sortedKeys(map[string]int{"c": 1, "a": 2, "b": 3})
+	if err != nil {
+		t.Fatalf("Failed to get keys: %v", err)
+	}
+
+	if joined := strings.Join(keys, ","); joined != "a,b,c" {
+		t.Errorf("Expected sorted keys a,b,c, but got %s.", joined)
+	}
+
+	keys, err = sortedKeys(testReport().Diffs)
+	if err != nil {
+		t.Fatalf("Failed to get keys: %v", err)
+	}
+
+	if len(keys) != 3 || keys[0] != "example.com/Compatible" {
+		t.Errorf("Expected sorted CRD names, but got %v.", keys)
+	}
+
+	for _, invalid := range []interface{}{nil, []string{"a"}, map[int]string{1: "a"}} {
+		if _, err := sortedKeys(invalid); err == nil {
+			t.Errorf("Expected %T to be rejected.", invalid)
+		}
+	}
+}
+
+func TestTemplateStyle(t *testing.T) {
+	enabled := color.Enable
+	defer func() { color.Enable = enabled }()
+
+	color.Enable = false
+
+	styled, err := templateStyle("crd", "example.com/Thing")
+	if err != nil {
+		t.Fatalf("Failed to style value: %v", err)
+	}
+
+	if styled != "example.com/Thing" {
+		t.Errorf("Expected uncolored value, but got %q.", styled)
+	}
+
+	color.Enable = true
+
+	styled, err = templateStyle("crd", "example.com/Thing")
+	if err != nil {
+		t.Fatalf("Failed to style value: %v", err)
+	}
+
+	if !strings.HasPrefix(styled, "\x1b[") || !strings.Contains(styled, "example.com/Thing") {
+		t.Errorf("Expected colored value, but got %q.", styled)
+	}
+
+	if _, err := templateStyle("unknown", "foo"); err == nil {
+		t.Error("Expected unknown style to be rejected.")
+	}
+}
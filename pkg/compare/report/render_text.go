@@ -20,20 +20,32 @@ import (
 func (r *Report) Render(breakingOnly bool) *indent.Indenter {
 	printer := indent.NewIndenter()
 
-	sortedIdentifiers := sets.List(sets.KeySet(r.Diffs))
+	sortedIdentifiers := sets.List(sets.KeySet(r.Diffs).Union(sets.KeySet(r.AddedCRDs)))
 
-	for i, crdIdentifier := range sortedIdentifiers {
-		crdChanges := r.Diffs[crdIdentifier]
+	for _, crdIdentifier := range sortedIdentifiers {
+		var crdRendered *indent.Indenter
 
-		if !shouldPrintCRD(&crdChanges, breakingOnly) {
-			continue
+		if addedCRD, exists := r.AddedCRDs[crdIdentifier]; exists {
+			// added CRDs are never breaking
+			if breakingOnly {
+				continue
+			}
+
+			crdRendered = renderAddedCRDAsText(crdIdentifier, &addedCRD)
+		} else {
+			crdChanges := r.Diffs[crdIdentifier]
+
+			if !shouldPrintCRD(&crdChanges, breakingOnly) {
+				continue
+			}
+
+			crdRendered = renderCRDDiffAsText(crdIdentifier, &crdChanges, breakingOnly)
 		}
 
-		if i > 0 {
+		if !printer.Empty() {
 			printer.AddLine("")
 		}
 
-		crdRendered := renderCRDDiffAsText(crdIdentifier, &crdChanges, breakingOnly)
 		printer.Add(crdRendered)
 	}
 
@@ -52,6 +64,10 @@ func renderCRDDiffAsText(crdIdentifier string, crdChanges *compare.CRDDiff, brea
 	unversionedChanges := indent.NewIndenter()
 
 	for _, change := range crdChanges.General {
+		if breakingOnly && !change.Breaking {
+			continue
+		}
+
 		unversionedChanges.AddLinef("~ %s", change.Description)
 	}
 
@@ -88,38 +104,62 @@ func renderCRDDiffAsText(crdIdentifier string, crdChanges *compare.CRDDiff, brea
 	return printer
 }
 
-func renderCRDVersionDiffAsText(version string, versionDiff *compare.CRDVersionDiff, breakingOnly bool) *indent.Indenter {
-	blocks := []*indent.Indenter{}
+func renderAddedCRDAsText(crdIdentifier string, addedCRD *AddedCRD) *indent.Indenter {
+	printer := indent.NewIndenter()
+	printer.AddLine(heading(crdIdentifier, "=", "crd"))
+	printer.Indent()
+	printer.AddLine("")
 
-	if !breakingOnly {
-		// ensure a stable, sorted order of paths
-		changedPaths := sets.List(sets.KeySet(versionDiff.SchemaChanges))
-		for _, path := range changedPaths {
-			pathChanges := versionDiff.SchemaChanges[path]
+	printer.AddLinef("+ %s CRD with %s scope", colors.ActionAdd.Render("added"), colors.NewValue.Render(addedCRD.Scope))
 
-			changes := indent.NewIndenter()
+	names := addedCRD.Names
+	printer.AddLinef("  %s: %s", colors.Attribute.Render("plural"), colors.NewValue.Render(names.Plural))
 
-			for _, field := range pathChanges.AddedProperties {
-				changes.AddLinef("+ %s %s", colors.ActionAdd.Render("added"), colors.Property.Render(field))
-			}
+	if names.Singular != "" {
+		printer.AddLinef("  %s: %s", colors.Attribute.Render("singular"), colors.NewValue.Render(names.Singular))
+	}
 
-			for _, field := range pathChanges.DeletedProperties {
-				changes.AddLinef("- %s %s", colors.ActionRemove.Render("removed"), colors.Property.Render(field))
-			}
+	if names.ListKind != "" {
+		printer.AddLinef("  %s: %s", colors.Attribute.Render("list kind"), colors.NewValue.Render(names.ListKind))
+	}
 
-			if d := pathChanges.Diff; d != nil {
-				printSchemaDiff(d, changes)
-			}
+	if len(names.ShortNames) > 0 {
+		printer.AddLinef("  %s: %s", colors.Attribute.Render("short names"), colors.NewValue.Render(strings.Join(names.ShortNames, ", ")))
+	}
 
-			if !changes.Empty() {
-				block := indent.NewIndenter()
-				block.AddLinef("%s:", colors.Path.Render(path))
-				block.Indent()
-				block.Add(changes)
+	if len(names.Categories) > 0 {
+		printer.AddLinef("  %s: %s", colors.Attribute.Render("categories"), colors.NewValue.Render(strings.Join(names.Categories, ", ")))
+	}
 
-				blocks = append(blocks, block)
-			}
+	for _, version := range addedCRD.Versions {
+		printer.AddLinef("+ %s %s", colors.ActionAdd.Render("added"), colors.Version.Render(version))
+	}
+
+	return printer
+}
+
+func renderCRDVersionDiffAsText(version string, versionDiff *compare.CRDVersionDiff, breakingOnly bool) *indent.Indenter {
+	blocks := []*indent.Indenter{}
+
+	general := indent.NewIndenter()
+	for _, change := range versionDiff.General {
+		if breakingOnly && !change.Breaking {
+			continue
 		}
+
+		general.AddLinef("~ %s", change.Description)
+	}
+
+	if !general.Empty() {
+		blocks = append(blocks, general)
+	}
+
+	if !breakingOnly {
+		blocks = append(blocks, renderSchemaChangesAsText(versionDiff)...)
+	}
+
+	if columns := renderPrinterColumnsDiffAsText(versionDiff.PrinterColumns, breakingOnly); columns != nil {
+		blocks = append(blocks, columns)
 	}
 
 	if len(versionDiff.BreakingChanges) > 0 {
@@ -154,6 +194,105 @@ func renderCRDVersionDiffAsText(version string, versionDiff *compare.CRDVersionD
 	return result
 }
 
+// renderSchemaChangesAsText returns one block per changed path.
+func renderSchemaChangesAsText(versionDiff *compare.CRDVersionDiff) []*indent.Indenter {
+	blocks := []*indent.Indenter{}
+
+	// ensure a stable, sorted order of paths
+	changedPaths := sets.List(sets.KeySet(versionDiff.SchemaChanges))
+	for _, path := range changedPaths {
+		pathChanges := versionDiff.SchemaChanges[path]
+
+		changes := indent.NewIndenter()
+
+		for _, field := range pathChanges.AddedProperties {
+			changes.AddLinef("+ %s %s", colors.ActionAdd.Render("added"), colors.Property.Render(field))
+		}
+
+		for _, field := range pathChanges.DeletedProperties {
+			changes.AddLinef("- %s %s", colors.ActionRemove.Render("removed"), colors.Property.Render(field))
+		}
+
+		if d := pathChanges.Diff; d != nil {
+			printSchemaDiff(d, changes)
+		}
+
+		printKubernetesSchemaChanges(&pathChanges, changes)
+
+		if !changes.Empty() {
+			block := indent.NewIndenter()
+			block.AddLinef("%s:", colors.Path.Render(path))
+			block.Indent()
+			block.Add(changes)
+
+			blocks = append(blocks, block)
+		}
+	}
+
+	return blocks
+}
+
+func renderPrinterColumnsDiffAsText(d *compare.PrinterColumnsDiff, breakingOnly bool) *indent.Indenter {
+	if d.Empty() {
+		return nil
+	}
+
+	changes := indent.NewIndenter()
+
+	if !breakingOnly {
+		for _, column := range d.Added {
+			changes.AddLinef("+ %s %s (%s)", colors.ActionAdd.Render("added"), colors.Property.Render(column.Name), colors.NewValue.Render(column.JSONPath))
+		}
+
+		for _, column := range d.Removed {
+			changes.AddLinef("- %s %s", colors.ActionRemove.Render("removed"), colors.Property.Render(column.Name))
+		}
+
+		for _, change := range d.Modified {
+			changes.AddLinef("~ %s %s", colors.ActionChange.Render("changed"), colors.Property.Render(change.Name))
+			changes.Indent()
+
+			b, r := change.Base, change.Revision
+			for _, attr := range []struct {
+				name     string
+				from, to interface{}
+			}{
+				{"type", b.Type, r.Type},
+				{"format", b.Format, r.Format},
+				{"JSONPath", b.JSONPath, r.JSONPath},
+				{"priority", b.Priority, r.Priority},
+				{"description", b.Description, r.Description},
+			} {
+				if attr.from != attr.to {
+					printValueDiff(attr.name, &diff.ValueDiff{From: attr.from, To: attr.to}, changes)
+				}
+			}
+
+			changes.Dedent()
+		}
+	}
+
+	for _, column := range d.Broken {
+		changes.AddLinef(
+			"! %s %s points to %s, which does not exist in the schema",
+			colors.ActionRemove.Render("broken"),
+			colors.Property.Render(column.Name),
+			colors.Path.Render(column.JSONPath),
+		)
+	}
+
+	if changes.Empty() {
+		return nil
+	}
+
+	block := indent.NewIndenter()
+	block.AddLinef("%s:", colors.Attribute.Render("printer columns"))
+	block.Indent()
+	block.Add(changes)
+
+	return block
+}
+
 func shouldPrintCRD(d *compare.CRDDiff, breakingOnly bool) bool {
 	if breakingOnly {
 		return d.HasBreakingChanges()
@@ -181,118 +320,248 @@ func heading(s, u string, style string) string {
 	return fmt.Sprintf("%s\n%s", s, line)
 }
 
-func printSchemaDiff(diff *diff.SchemaDiff, printer *indent.Indenter) {
-	if diff.ExtensionsDiff != nil {
-		printer.AddLinef("ExtensionsDiff: %#v", diff.ExtensionsDiff)
+// printSchemaDiff prints all changes of an oasdiff SchemaDiff. Nested
+// schemas (like in anyOf or not) are printed recursively.
+func printSchemaDiff(d *diff.SchemaDiff, printer *indent.Indenter) {
+	if d.SchemaAdded {
+		printer.AddLinef("+ %s schema", colors.ActionAdd.Render("added"))
 	}
-	if diff.OneOfDiff != nil {
-		printer.AddLinef("OneOfDiff: %#v", diff.OneOfDiff)
+	if d.SchemaDeleted {
+		printer.AddLinef("- %s schema", colors.ActionRemove.Render("removed"))
 	}
-	if diff.AnyOfDiff != nil {
-		printer.AddLinef("AnyOfDiff: %#v", diff.AnyOfDiff)
+	if d.CircularRefDiff {
+		printer.AddLinef("~ %s %s", colors.ActionChange.Render("changed"), colors.Attribute.Render("circular reference"))
 	}
-	if diff.AllOfDiff != nil {
-		printer.AddLinef("AllOfDiff: %#v", diff.AllOfDiff)
+	for _, attr := range SchemaValueAttributes {
+		if vd := *attr.Field(d); vd != nil {
+			printValueDiff(attr.Label, vd, printer)
+		}
 	}
-	if diff.NotDiff != nil {
-		printer.AddLinef("NotDiff: %#v", diff.NotDiff)
+	if ed := d.EnumDiff; ed != nil {
+		printEnumDiff(ed, printer)
 	}
-	if d := diff.TypeDiff; d != nil {
-		printValueDiff("type", d, printer)
+	if rd := d.RequiredDiff; rd != nil {
+		if items := rd.Added; len(items) > 0 {
+			printer.AddLinef("~ %s %v", colors.ActionChange.Render("requires"), colors.Property.Render(items))
+		}
+
+		if items := rd.Deleted; len(items) > 0 {
+			printer.AddLinef("~ %s %v", colors.ActionChange.Render("unrequires"), colors.Property.Render(items))
+		}
 	}
-	if d := diff.TitleDiff; d != nil {
-		printValueDiff("title", d, printer)
+	if ed := d.ExtensionsDiff; ed != nil {
+		printInterfaceMapDiff("extension", (*diff.InterfaceMapDiff)(ed), printer)
 	}
-	if d := diff.FormatDiff; d != nil {
-		printValueDiff("format", d, printer)
+	if dd := d.ExternalDocsDiff; dd != nil {
+		printAddedOrRemoved("external docs", dd.Added, dd.Deleted, printer)
+		if vd := dd.DescriptionDiff; vd != nil {
+			printValueDiff("external docs description", vd, printer)
+		}
+		if vd := dd.URLDiff; vd != nil {
+			printValueDiff("external docs URL", vd, printer)
+		}
+		if ed := dd.ExtensionsDiff; ed != nil {
+			printInterfaceMapDiff("external docs extension", (*diff.InterfaceMapDiff)(ed), printer)
+		}
 	}
-	if d := diff.DescriptionDiff; d != nil {
-		printValueDiff("description", d, printer)
+	if dd := d.DiscriminatorDiff; dd != nil {
+		printAddedOrRemoved("discriminator", dd.Added, dd.Deleted, printer)
+		if vd := dd.PropertyNameDiff; vd != nil {
+			printValueDiff("discriminator property name", vd, printer)
+		}
+		if md := dd.MappingDiff; md != nil {
+			printStringMapDiff("discriminator mapping", md, printer)
+		}
+		if ed := dd.ExtensionsDiff; ed != nil {
+			printInterfaceMapDiff("discriminator extension", (*diff.InterfaceMapDiff)(ed), printer)
+		}
 	}
-	if diff.EnumDiff != nil {
-		printer.AddLinef("EnumDiff: %#v", diff.EnumDiff)
+	// properties of the schema itself are reported as separate paths by the
+	// compare package, so these are only found in nested schemas
+	if pd := d.PropertiesDiff; pd != nil {
+		for _, name := range sortedStrings(pd.Added) {
+			printer.AddLinef("+ %s %s", colors.ActionAdd.Render("added"), colors.Property.Render(name))
+		}
+		for _, name := range sortedStrings(pd.Deleted) {
+			printer.AddLinef("- %s %s", colors.ActionRemove.Render("removed"), colors.Property.Render(name))
+		}
+		for _, name := range sets.List(sets.KeySet(pd.Modified)) {
+			printNestedSchemaDiff(colors.Property.Render(name), pd.Modified[name], printer)
+		}
 	}
-	if d := diff.DefaultDiff; d != nil {
-		printValueDiff("default value", d, printer)
+	for _, comp := range SchemaCompositions {
+		if ld := *comp.Field(d); ld != nil {
+			printSchemaListDiff(comp.Name, ld, printer)
+		}
 	}
-	if d := diff.ExampleDiff; d != nil {
-		printValueDiff("example", d, printer)
+	if d.NotDiff != nil {
+		printNestedSchemaDiff(colors.Attribute.Render("not"), d.NotDiff, printer)
 	}
-	if diff.ExternalDocsDiff != nil {
-		printer.AddLinef("ExternalDocsDiff: %#v", diff.ExternalDocsDiff)
+	if d.ItemsDiff != nil {
+		printNestedSchemaDiff(colors.Attribute.Render("items"), d.ItemsDiff, printer)
 	}
-	if diff.AdditionalPropertiesAllowedDiff != nil {
-		printer.AddLinef("AdditionalPropertiesAllowedDiff: %#v", diff.AdditionalPropertiesAllowedDiff)
+	if d.AdditionalPropertiesDiff != nil {
+		printNestedSchemaDiff(colors.Attribute.Render("additional properties"), d.AdditionalPropertiesDiff, printer)
 	}
-	if diff.UniqueItemsDiff != nil {
-		printer.AddLinef("UniqueItemsDiff: %#v", diff.UniqueItemsDiff)
+}
+
+// printNestedSchemaDiff prints the changes of a nested schema below a
+// "~ changed <name>:" line.
+func printNestedSchemaDiff(name string, d *diff.SchemaDiff, printer *indent.Indenter) {
+	printer.AddLinef("~ %s %s:", colors.ActionChange.Render("changed"), name)
+	printer.Indent()
+	printSchemaDiff(d, printer)
+	printer.Dedent()
+}
+
+// printEnumDiff prints enum changes, e.g. "~ changed enum: added [Foo], removed [Bar]".
+func printEnumDiff(d *diff.EnumDiff, printer *indent.Indenter) {
+	printAddedOrRemoved("enum", d.EnumAdded, d.EnumDeleted, printer)
+
+	changes := []string{}
+	if len(d.Added) > 0 {
+		changes = append(changes, fmt.Sprintf("%s %s", colors.ActionAdd.Render("added"), colors.NewValue.Render(formatValues(d.Added))))
 	}
-	if diff.ExclusiveMinDiff != nil {
-		printer.AddLinef("ExclusiveMinDiff: %#v", diff.ExclusiveMinDiff)
+	if len(d.Deleted) > 0 {
+		changes = append(changes, fmt.Sprintf("%s %s", colors.ActionRemove.Render("removed"), colors.OldValue.Render(formatValues(d.Deleted))))
 	}
-	if diff.ExclusiveMaxDiff != nil {
-		printer.AddLinef("ExclusiveMaxDiff: %#v", diff.ExclusiveMaxDiff)
+
+	if len(changes) > 0 {
+		printer.AddLinef("~ %s %s: %s", colors.ActionChange.Render("changed"), colors.Attribute.Render("enum"), strings.Join(changes, ", "))
 	}
-	if d := diff.NullableDiff; d != nil {
-		printValueDiff("nullable", d, printer)
+}
+
+// printSchemaListDiff prints the changes to anyOf, oneOf or allOf.
+func printSchemaListDiff(name string, d *diff.SchemaListDiff, printer *indent.Indenter) {
+	attribute := colors.Attribute.Render(name)
+
+	for _, schema := range sortedStrings(d.Added) {
+		printer.AddLinef("+ %s %s subschema %s", colors.ActionAdd.Render("added"), attribute, colors.NewValue.Render(schema))
 	}
-	if diff.ReadOnlyDiff != nil {
-		printer.AddLinef("ReadOnlyDiff: %#v", diff.ReadOnlyDiff)
+	for _, schema := range sortedStrings(d.Deleted) {
+		printer.AddLinef("- %s %s subschema %s", colors.ActionRemove.Render("removed"), attribute, colors.OldValue.Render(schema))
 	}
-	if diff.WriteOnlyDiff != nil {
-		printer.AddLinef("WriteOnlyDiff: %#v", diff.WriteOnlyDiff)
+	for _, schema := range sets.List(sets.KeySet(d.Modified)) {
+		printNestedSchemaDiff(fmt.Sprintf("%s subschema %s", attribute, colors.Property.Render(schema)), d.Modified[schema], printer)
 	}
-	if diff.AllowEmptyValueDiff != nil {
-		printer.AddLinef("AllowEmptyValueDiff: %#v", diff.AllowEmptyValueDiff)
+}
+
+// printInterfaceMapDiff prints changes to maps like the schema extensions.
+func printInterfaceMapDiff(name string, d *diff.InterfaceMapDiff, printer *indent.Indenter) {
+	for _, key := range sortedStrings(d.Added) {
+		printer.AddLinef("+ %s %s %s", colors.ActionAdd.Render("added"), name, colors.Attribute.Render(key))
 	}
-	if diff.XMLDiff != nil {
-		printer.AddLinef("XMLDiff: %#v", diff.XMLDiff)
+	for _, key := range sortedStrings(d.Deleted) {
+		printer.AddLinef("- %s %s %s", colors.ActionRemove.Render("removed"), name, colors.Attribute.Render(key))
 	}
-	if diff.DeprecatedDiff != nil {
-		printer.AddLinef("DeprecatedDiff: %#v", diff.DeprecatedDiff)
+	for _, key := range sets.List(sets.KeySet(d.Modified)) {
+		printValueDiff(fmt.Sprintf("%s %s", name, key), d.Modified[key], printer)
 	}
-	if d := diff.MinDiff; d != nil {
-		printValueDiff("minimum allowed value", d, printer)
+}
+
+func printStringMapDiff(name string, d *diff.StringMapDiff, printer *indent.Indenter) {
+	printInterfaceMapDiff(name, &diff.InterfaceMapDiff{
+		Added:    d.Added,
+		Deleted:  d.Deleted,
+		Modified: diff.ModifiedInterfaces(d.Modified),
+	}, printer)
+}
+
+func printAddedOrRemoved(attribute string, added bool, removed bool, printer *indent.Indenter) {
+	if added {
+		printer.AddLinef("+ %s %s", colors.ActionAdd.Render("added"), colors.Attribute.Render(attribute))
+	}
+	if removed {
+		printer.AddLinef("- %s %s", colors.ActionRemove.Render("removed"), colors.Attribute.Render(attribute))
+	}
+}
+
+func formatValues(values []interface{}) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = formatValue(value)
 	}
-	if d := diff.MaxDiff; d != nil {
-		printValueDiff("maximum allowed value", d, printer)
+
+	return joinList(formatted)
+}
+
+func sortedStrings(items []string) []string {
+	return sets.List(sets.New(items...))
+}
+
+// printKubernetesSchemaChanges prints the changes to Kubernetes-specific
+// extensions, which are detected by crdiff itself and not by oasdiff.
+func printKubernetesSchemaChanges(d *compare.CRDSchemaDiff, printer *indent.Indenter) {
+	if c := d.ListType; c != nil {
+		printValueDiff("list type", &diff.ValueDiff{From: c.From, To: c.To}, printer)
 	}
-	if diff.MultipleOfDiff != nil {
-		printer.AddLinef("MultipleOfDiff: %#v", diff.MultipleOfDiff)
+	if c := d.ListMapKeys; c != nil {
+		printValueDiff("list map keys", &diff.ValueDiff{From: joinList(c.From), To: joinList(c.To)}, printer)
 	}
-	if d := diff.MinLengthDiff; d != nil {
-		printValueDiff("minimum required length", d, printer)
+	if c := d.ValidationRules; c != nil {
+		printValidationRulesDiff(c, printer)
 	}
-	if d := diff.MaxLengthDiff; d != nil {
-		printValueDiff("maximum allowed length", d, printer)
+	if c := d.PreserveUnknownFields; c != nil {
+		printValueDiff("preserve unknown fields", &diff.ValueDiff{From: c.From, To: c.To}, printer)
 	}
-	if d := diff.PatternDiff; d != nil {
-		printValueDiff("pattern", d, printer)
+	if c := d.EmbeddedResource; c != nil {
+		printValueDiff("embedded resource", &diff.ValueDiff{From: c.From, To: c.To}, printer)
 	}
-	if d := diff.MinItemsDiff; d != nil {
-		printValueDiff("minimum required items", d, printer)
+	if c := d.MapType; c != nil {
+		printValueDiff("map type", &diff.ValueDiff{From: c.From, To: c.To}, printer)
 	}
-	if d := diff.MaxItemsDiff; d != nil {
-		printValueDiff("maximum allowed items", d, printer)
+	for _, name := range d.PrunedProperties {
+		printer.AddLinef("! unknown fields in new property %s will be %s", colors.NewValue.Render(name), colors.ActionRemove.Render("pruned"))
 	}
-	if d := diff.RequiredDiff; d != nil {
-		if items := d.Added; len(items) > 0 {
-			printer.AddLinef("~ %s %v", colors.ActionChange.Render("requires"), colors.Property.Render(items))
+}
+
+func printValidationRulesDiff(d *compare.ValidationRulesDiff, printer *indent.Indenter) {
+	for _, rule := range d.Added {
+		printer.AddLinef("+ %s validation rule %s", colors.ActionAdd.Render("added"), colors.NewValue.Render(rule.Rule))
+	}
+
+	for _, rule := range d.Removed {
+		printer.AddLinef("- %s validation rule %s", colors.ActionRemove.Render("removed"), colors.OldValue.Render(rule.Rule))
+	}
+
+	for _, change := range d.Modified {
+		from, to := change.From, change.To
+
+		if from.Rule != to.Rule {
+			printer.AddLinef(
+				"~ %s validation rule from %s to %s",
+				colors.ActionChange.Render("changed"),
+				colors.OldValue.Render(from.Rule),
+				colors.NewValue.Render(to.Rule),
+			)
+		} else {
+			printer.AddLinef("~ %s validation rule %s", colors.ActionChange.Render("changed"), colors.NewValue.Render(to.Rule))
 		}
 
-		if items := d.Deleted; len(items) > 0 {
-			printer.AddLinef("~ %s %v", colors.ActionChange.Render("unrequires"), colors.Property.Render(items))
+		printer.Indent()
+		printValueDiffIfChanged("message", from.Message, to.Message, printer)
+		printValueDiffIfChanged("message expression", from.MessageExpression, to.MessageExpression, printer)
+		printValueDiffIfChanged("reason", from.Reason, to.Reason, printer)
+		printValueDiffIfChanged("field path", from.FieldPath, to.FieldPath, printer)
+		if from.OptionalOldSelf != to.OptionalOldSelf {
+			printValueDiff("optionalOldSelf", &diff.ValueDiff{From: from.OptionalOldSelf, To: to.OptionalOldSelf}, printer)
 		}
+		printer.Dedent()
 	}
-	if diff.MinPropsDiff != nil {
-		printer.AddLinef("MinPropsDiff: %#v", diff.MinPropsDiff)
-	}
-	if diff.MaxPropsDiff != nil {
-		printer.AddLinef("MaxPropsDiff: %#v", diff.MaxPropsDiff)
+}
+
+func printValueDiffIfChanged(attribute string, from, to string, printer *indent.Indenter) {
+	if from != to {
+		printValueDiff(attribute, &diff.ValueDiff{From: from, To: to}, printer)
 	}
-	if diff.DiscriminatorDiff != nil {
-		printer.AddLinef("DiscriminatorDiff: %#v", diff.DiscriminatorDiff)
+}
+
+func joinList(items []string) string {
+	if len(items) == 0 {
+		return ""
 	}
+
+	return "[" + strings.Join(items, ", ") + "]"
 }
 
 func isEmpty(s string) bool {
@@ -300,17 +569,17 @@ func isEmpty(s string) bool {
 }
 
 func printValueDiff(attribute string, diff *diff.ValueDiff, printer *indent.Indenter) {
-	from := fmt.Sprintf("%v", diff.From)
+	from := formatValue(diff.From)
 
 	if isEmpty(from) {
 		printer.AddLinef(
 			"~ %s %s to %s",
 			colors.ActionChange.Render("set"),
 			colors.Attribute.Render(attribute),
-			colors.NewValue.Render(diff.To),
+			colors.NewValue.Render(formatValue(diff.To)),
 		)
 	} else {
-		to := fmt.Sprintf("%v", diff.To)
+		to := formatValue(diff.To)
 
 		if isEmpty(to) {
 			printer.AddLinef(
@@ -323,8 +592,8 @@ func printValueDiff(attribute string, diff *diff.ValueDiff, printer *indent.Inde
 				"~ %s %s from %s to %s",
 				colors.ActionChange.Render("changed"),
 				colors.Attribute.Render(attribute),
-				colors.OldValue.Render(diff.From),
-				colors.NewValue.Render(diff.To),
+				colors.OldValue.Render(from),
+				colors.NewValue.Render(to),
 			)
 		}
 	}
@@ -354,8 +623,41 @@ func renderBreakingChange(b compare.BreakingChange) string {
 		return fmt.Sprintf("~ %s of %s was %s to %s.", colors.Attribute.Render("Minimum number of items"), p(msg.Path), colors.ActionAdd.Render("set"), colors.NewValue.Render(msg.Items))
 	case *oasdiff.PropertyMinItemsIncreasedMessage:
 		return fmt.Sprintf("~ %s in %s was %s from %s to %s.", colors.Attribute.Render("Minimum number of items"), p(msg.Path), colors.ActionChange.Render("increased"), colors.OldValue.Render(msg.From), colors.NewValue.Render(msg.To))
+	case *oasdiff.PropertyEnumValueRemovedMessage:
+		return fmt.Sprintf("- The %s %s of %s was %s.", colors.Attribute.Render("enum value"), colors.OldValue.Render(msg.Value), p(msg.Path), colors.ActionRemove.Render("removed"))
+	case *oasdiff.PropertyMaxLengthDecreasedMessage:
+		return fmt.Sprintf("~ %s of %s was %s to %s.", colors.Attribute.Render("Maximum length"), p(msg.Path), colors.ActionChange.Render("decreased"), colors.NewValue.Render(msg.Length))
+	case *oasdiff.PropertyMinSetMessage:
+		return fmt.Sprintf("~ %s of %s was %s to %s.", colors.Attribute.Render("Minimum"), p(msg.Path), colors.ActionAdd.Render("set"), colors.NewValue.Render(msg.Value))
+	case *oasdiff.PropertyMinIncreasedMessage:
+		return fmt.Sprintf("~ %s of %s was %s to %s.", colors.Attribute.Render("Minimum"), p(msg.Path), colors.ActionChange.Render("increased"), colors.NewValue.Render(msg.Value))
+	case *oasdiff.PropertyMaxSetMessage:
+		return fmt.Sprintf("~ %s of %s was %s to %s.", colors.Attribute.Render("Maximum"), p(msg.Path), colors.ActionAdd.Render("set"), colors.NewValue.Render(msg.Value))
+	case *oasdiff.PropertyMaxDecreasedMessage:
+		return fmt.Sprintf("~ %s of %s was %s to %s.", colors.Attribute.Render("Maximum"), p(msg.Path), colors.ActionChange.Render("decreased"), colors.NewValue.Render(msg.Value))
+	case *oasdiff.PropertyBecameNotNullableMessage:
+		return fmt.Sprintf("~ %s is %s nullable.", p(msg.Path), colors.ActionRemove.Render("no longer"))
 	case *oasdiff.PropertyPatternAddedMessage:
 		return fmt.Sprintf("~ A %s for %s was %s to %s.", colors.Attribute.Render("pattern"), p(msg.Path), colors.ActionAdd.Render("set"), colors.NewValue.Render(msg.Pattern))
+	case *compare.ListTypeChangedMessage:
+		return fmt.Sprintf("~ The %s of %s was %s from %s to %s.", colors.Attribute.Render("list type"), p(msg.Path), colors.ActionChange.Render("changed"), colors.OldValue.Render(msg.From), colors.NewValue.Render(msg.To))
+	case *compare.ListMapKeysChangedMessage:
+		return fmt.Sprintf("~ The %s of %s were %s from %s to %s.", colors.Attribute.Render("list map keys"), p(msg.Path), colors.ActionChange.Render("changed"), colors.OldValue.Render(joinList(msg.From)), colors.NewValue.Render(joinList(msg.To)))
+	case *compare.ValidationRuleAddedMessage:
+		return fmt.Sprintf("+ The %s %s was %s to %s.", colors.Attribute.Render("validation rule"), colors.NewValue.Render(msg.Rule), colors.ActionAdd.Render("added"), p(msg.Path))
+	case *compare.ValidationRuleChangedMessage:
+		if msg.From == msg.To {
+			return fmt.Sprintf("~ The %s %s of %s was %s.", colors.Attribute.Render("validation rule"), colors.NewValue.Render(msg.To), p(msg.Path), colors.ActionChange.Render("changed"))
+		}
+		return fmt.Sprintf("~ A %s of %s was %s from %s to %s.", colors.Attribute.Render("validation rule"), p(msg.Path), colors.ActionChange.Render("changed"), colors.OldValue.Render(msg.From), colors.NewValue.Render(msg.To))
+	case *compare.UnknownFieldsPrunedMessage:
+		return fmt.Sprintf("! Unknown fields in %s will be %s, which silently deletes stored data.", p(msg.Path), colors.ActionRemove.Render("pruned"))
+	case *compare.EmbeddedResourceEnabledMessage:
+		return fmt.Sprintf("! %s was turned into an %s, which requires apiVersion and kind to be set.", p(msg.Path), colors.Attribute.Render("embedded resource"))
+	case *compare.EmbeddedResourceDisabledMessage:
+		return fmt.Sprintf("! %s is no longer an %s, so its %s will be %s.", p(msg.Path), colors.Attribute.Render("embedded resource"), colors.Property.Render(joinList(msg.Fields)), colors.ActionRemove.Render("pruned"))
+	case *compare.MapTypeChangedMessage:
+		return fmt.Sprintf("~ The %s of %s was %s from %s to %s.", colors.Attribute.Render("map type"), p(msg.Path), colors.ActionChange.Render("changed"), colors.OldValue.Render(msg.From), colors.NewValue.Render(msg.To))
 	case *oasdiff.PropertyPatternChangedMessage:
 		return fmt.Sprintf("~ The %s for %s was %s from %s to %s.", colors.Attribute.Render("pattern"), p(msg.Path), colors.ActionChange.Render("changed"), colors.OldValue.Render(msg.From), colors.NewValue.Render(msg.To))
 	}
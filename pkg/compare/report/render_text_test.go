@@ -0,0 +1,390 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tufin/oasdiff/diff"
+
+	"go.xrstf.de/crdiff/pkg/indent"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestRenderAddedCRD(t *testing.T) {
+	defer disableColors()()
+
+	r := &Report{
+		AddedCRDs: map[string]AddedCRD{
+			"example.com/Thing": {
+				Scope:    "Namespaced",
+				Versions: []string{"v1", "v1alpha1"},
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural:     "things",
+					Singular:   "thing",
+					ShortNames: []string{"th", "thg"},
+					Kind:       "Thing",
+					ListKind:   "ThingList",
+					Categories: []string{"all"},
+				},
+			},
+		},
+	}
+
+	expected := strings.Join([]string{
+		"example.com/Thing",
+		"=================",
+		"  ",
+		"  + added CRD with Namespaced scope",
+		"    plural: things",
+		"    singular: thing",
+		"    list kind: ThingList",
+		"    short names: th, thg",
+		"    categories: all",
+		"  + added v1",
+		"  + added v1alpha1",
+	}, "\n")
+
+	if output := strings.TrimRight(r.Render(false).String(), "\n"); output != expected {
+		t.Errorf("Expected\n\n%s\n\nbut got\n\n%s", expected, output)
+	}
+
+	// adding a CRD is never breaking
+	if output := r.Render(true).String(); output != "" {
+		t.Errorf("Expected no output when only rendering breaking changes, but got\n\n%s", output)
+	}
+}
+
+func TestPrintSchemaDiff(t *testing.T) {
+	defer disableColors()()
+
+	five := uint64(5)
+	ten := uint64(10)
+	half := 0.5
+
+	testcases := []struct {
+		name     string
+		diff     *diff.SchemaDiff
+		expected string
+	}{
+		{
+			name:     "schema added",
+			diff:     &diff.SchemaDiff{SchemaAdded: true},
+			expected: "+ added schema",
+		},
+		{
+			name:     "schema removed",
+			diff:     &diff.SchemaDiff{SchemaDeleted: true},
+			expected: "- removed schema",
+		},
+		{
+			name:     "circular reference",
+			diff:     &diff.SchemaDiff{CircularRefDiff: true},
+			expected: "~ changed circular reference",
+		},
+		{
+			name:     "type",
+			diff:     &diff.SchemaDiff{TypeDiff: &diff.ValueDiff{From: "string", To: "integer"}},
+			expected: "~ changed type from string to integer",
+		},
+		{
+			name:     "title",
+			diff:     &diff.SchemaDiff{TitleDiff: &diff.ValueDiff{From: nil, To: "Foo"}},
+			expected: "~ set title to Foo",
+		},
+		{
+			name:     "format",
+			diff:     &diff.SchemaDiff{FormatDiff: &diff.ValueDiff{From: "int32", To: nil}},
+			expected: "~ removed format",
+		},
+		{
+			name:     "description",
+			diff:     &diff.SchemaDiff{DescriptionDiff: &diff.ValueDiff{From: "old", To: "new"}},
+			expected: "~ changed description from old to new",
+		},
+		{
+			name:     "default value",
+			diff:     &diff.SchemaDiff{DefaultDiff: &diff.ValueDiff{From: map[string]interface{}{"a": 1}, To: []interface{}{"b"}}},
+			expected: `~ changed default value from {"a":1} to ["b"]`,
+		},
+		{
+			name:     "example",
+			diff:     &diff.SchemaDiff{ExampleDiff: &diff.ValueDiff{From: "foo", To: "bar"}},
+			expected: "~ changed example from foo to bar",
+		},
+		{
+			name:     "additional properties allowed",
+			diff:     &diff.SchemaDiff{AdditionalPropertiesAllowedDiff: &diff.ValueDiff{From: true, To: false}},
+			expected: "~ changed additional properties allowed from true to false",
+		},
+		{
+			name:     "unique items",
+			diff:     &diff.SchemaDiff{UniqueItemsDiff: &diff.ValueDiff{From: false, To: true}},
+			expected: "~ changed unique items from false to true",
+		},
+		{
+			name:     "exclusive minimum",
+			diff:     &diff.SchemaDiff{ExclusiveMinDiff: &diff.ValueDiff{From: false, To: true}},
+			expected: "~ changed exclusive minimum from false to true",
+		},
+		{
+			name:     "exclusive maximum",
+			diff:     &diff.SchemaDiff{ExclusiveMaxDiff: &diff.ValueDiff{From: true, To: false}},
+			expected: "~ changed exclusive maximum from true to false",
+		},
+		{
+			name:     "nullable",
+			diff:     &diff.SchemaDiff{NullableDiff: &diff.ValueDiff{From: false, To: true}},
+			expected: "~ changed nullable from false to true",
+		},
+		{
+			name:     "read only",
+			diff:     &diff.SchemaDiff{ReadOnlyDiff: &diff.ValueDiff{From: false, To: true}},
+			expected: "~ changed read only from false to true",
+		},
+		{
+			name:     "write only",
+			diff:     &diff.SchemaDiff{WriteOnlyDiff: &diff.ValueDiff{From: true, To: false}},
+			expected: "~ changed write only from true to false",
+		},
+		{
+			name:     "allow empty value",
+			diff:     &diff.SchemaDiff{AllowEmptyValueDiff: &diff.ValueDiff{From: false, To: true}},
+			expected: "~ changed allow empty value from false to true",
+		},
+		{
+			name:     "xml",
+			diff:     &diff.SchemaDiff{XMLDiff: &diff.ValueDiff{From: nil, To: map[string]interface{}{"name": "foo"}}},
+			expected: `~ set XML to {"name":"foo"}`,
+		},
+		{
+			name:     "deprecated",
+			diff:     &diff.SchemaDiff{DeprecatedDiff: &diff.ValueDiff{From: false, To: true}},
+			expected: "~ changed deprecated from false to true",
+		},
+		{
+			name:     "minimum",
+			diff:     &diff.SchemaDiff{MinDiff: &diff.ValueDiff{From: (*float64)(nil), To: &half}},
+			expected: "~ set minimum allowed value to 0.5",
+		},
+		{
+			name:     "maximum",
+			diff:     &diff.SchemaDiff{MaxDiff: &diff.ValueDiff{From: &half, To: (*float64)(nil)}},
+			expected: "~ removed maximum allowed value",
+		},
+		{
+			name:     "multiple of",
+			diff:     &diff.SchemaDiff{MultipleOfDiff: &diff.ValueDiff{From: &half, To: 2.0}},
+			expected: "~ changed multiple of from 0.5 to 2",
+		},
+		{
+			name:     "minimum length",
+			diff:     &diff.SchemaDiff{MinLengthDiff: &diff.ValueDiff{From: uint64(1), To: uint64(2)}},
+			expected: "~ changed minimum required length from 1 to 2",
+		},
+		{
+			name:     "maximum length",
+			diff:     &diff.SchemaDiff{MaxLengthDiff: &diff.ValueDiff{From: &five, To: &ten}},
+			expected: "~ changed maximum allowed length from 5 to 10",
+		},
+		{
+			name:     "pattern",
+			diff:     &diff.SchemaDiff{PatternDiff: &diff.ValueDiff{From: "^a$", To: "^b$"}},
+			expected: "~ changed pattern from ^a$ to ^b$",
+		},
+		{
+			name:     "minimum items",
+			diff:     &diff.SchemaDiff{MinItemsDiff: &diff.ValueDiff{From: uint64(0), To: uint64(1)}},
+			expected: "~ changed minimum required items from 0 to 1",
+		},
+		{
+			name:     "maximum items",
+			diff:     &diff.SchemaDiff{MaxItemsDiff: &diff.ValueDiff{From: (*uint64)(nil), To: &ten}},
+			expected: "~ set maximum allowed items to 10",
+		},
+		{
+			name:     "minimum properties",
+			diff:     &diff.SchemaDiff{MinPropsDiff: &diff.ValueDiff{From: uint64(0), To: uint64(1)}},
+			expected: "~ changed minimum required properties from 0 to 1",
+		},
+		{
+			name:     "maximum properties",
+			diff:     &diff.SchemaDiff{MaxPropsDiff: &diff.ValueDiff{From: &five, To: &ten}},
+			expected: "~ changed maximum allowed properties from 5 to 10",
+		},
+		{
+			name: "enum values",
+			diff: &diff.SchemaDiff{EnumDiff: &diff.EnumDiff{
+				Added:   diff.EnumValues{"Foo", "Baz"},
+				Deleted: diff.EnumValues{"Bar"},
+			}},
+			expected: "~ changed enum: added [Foo, Baz], removed [Bar]",
+		},
+		{
+			name: "enum added",
+			diff: &diff.SchemaDiff{EnumDiff: &diff.EnumDiff{
+				EnumAdded: true,
+				Added:     diff.EnumValues{1, 2},
+			}},
+			expected: "+ added enum\n~ changed enum: added [1, 2]",
+		},
+		{
+			name:     "enum removed",
+			diff:     &diff.SchemaDiff{EnumDiff: &diff.EnumDiff{EnumDeleted: true}},
+			expected: "- removed enum",
+		},
+		{
+			name: "required",
+			diff: &diff.SchemaDiff{RequiredDiff: &diff.RequiredPropertiesDiff{StringsDiff: diff.StringsDiff{
+				Added:   []string{"foo"},
+				Deleted: []string{"bar"},
+			}}},
+			expected: "~ requires [foo]\n~ unrequires [bar]",
+		},
+		{
+			name: "extensions",
+			diff: &diff.SchemaDiff{ExtensionsDiff: &diff.ExtensionsDiff{
+				Added:   []string{"x-new"},
+				Deleted: []string{"x-old"},
+				Modified: diff.ModifiedInterfaces{
+					"x-kubernetes-validations": {
+						From: []interface{}{map[string]interface{}{"rule": "self > 0"}},
+						To:   []interface{}{map[string]interface{}{"rule": "self > 1"}},
+					},
+				},
+			}},
+			expected: strings.Join([]string{
+				"+ added extension x-new",
+				"- removed extension x-old",
+				`~ changed extension x-kubernetes-validations from [{"rule":"self > 0"}] to [{"rule":"self > 1"}]`,
+			}, "\n"),
+		},
+		{
+			name: "external docs",
+			diff: &diff.SchemaDiff{ExternalDocsDiff: &diff.ExternalDocsDiff{
+				Added:           true,
+				DescriptionDiff: &diff.ValueDiff{From: nil, To: "docs"},
+				URLDiff:         &diff.ValueDiff{From: nil, To: "https://example.com"},
+			}},
+			expected: strings.Join([]string{
+				"+ added external docs",
+				"~ set external docs description to docs",
+				"~ set external docs URL to https://example.com",
+			}, "\n"),
+		},
+		{
+			name: "discriminator",
+			diff: &diff.SchemaDiff{DiscriminatorDiff: &diff.DiscriminatorDiff{
+				PropertyNameDiff: &diff.ValueDiff{From: "kind", To: "type"},
+				MappingDiff: &diff.StringMapDiff{
+					Added:    []string{"dog"},
+					Modified: diff.ModifiedKeys{"cat": {From: "#/Cat", To: "#/Feline"}},
+				},
+			}},
+			expected: strings.Join([]string{
+				"~ changed discriminator property name from kind to type",
+				"+ added discriminator mapping dog",
+				"~ changed discriminator mapping cat from #/Cat to #/Feline",
+			}, "\n"),
+		},
+		{
+			name: "properties",
+			diff: &diff.SchemaDiff{PropertiesDiff: &diff.SchemasDiff{
+				Added:   []string{"foo"},
+				Deleted: []string{"bar"},
+				Modified: diff.ModifiedSchemas{
+					"baz": {TypeDiff: &diff.ValueDiff{From: "string", To: "integer"}},
+				},
+			}},
+			expected: strings.Join([]string{
+				"+ added foo",
+				"- removed bar",
+				"~ changed baz:",
+				"  ~ changed type from string to integer",
+			}, "\n"),
+		},
+		{
+			name: "anyOf",
+			diff: &diff.SchemaDiff{AnyOfDiff: &diff.SchemaListDiff{
+				Added:   []string{"#2"},
+				Deleted: []string{"#3"},
+				Modified: diff.ModifiedSchemas{
+					"#1": {FormatDiff: &diff.ValueDiff{From: "int32", To: "int64"}},
+				},
+			}},
+			expected: strings.Join([]string{
+				"+ added anyOf subschema #2",
+				"- removed anyOf subschema #3",
+				"~ changed anyOf subschema #1:",
+				"  ~ changed format from int32 to int64",
+			}, "\n"),
+		},
+		{
+			name: "oneOf",
+			diff: &diff.SchemaDiff{OneOfDiff: &diff.SchemaListDiff{
+				Added: []string{"#1"},
+			}},
+			expected: "+ added oneOf subschema #1",
+		},
+		{
+			name: "allOf",
+			diff: &diff.SchemaDiff{AllOfDiff: &diff.SchemaListDiff{
+				Modified: diff.ModifiedSchemas{
+					"#1": {RequiredDiff: &diff.RequiredPropertiesDiff{StringsDiff: diff.StringsDiff{Added: []string{"foo"}}}},
+				},
+			}},
+			expected: "~ changed allOf subschema #1:\n  ~ requires [foo]",
+		},
+		{
+			name: "not",
+			diff: &diff.SchemaDiff{NotDiff: &diff.SchemaDiff{
+				EnumDiff: &diff.EnumDiff{Deleted: diff.EnumValues{"a"}},
+			}},
+			expected: "~ changed not:\n  ~ changed enum: removed [a]",
+		},
+		{
+			name: "items",
+			diff: &diff.SchemaDiff{ItemsDiff: &diff.SchemaDiff{
+				MaxLengthDiff: &diff.ValueDiff{From: &five, To: &ten},
+			}},
+			expected: "~ changed items:\n  ~ changed maximum allowed length from 5 to 10",
+		},
+		{
+			name: "additional properties",
+			diff: &diff.SchemaDiff{AdditionalPropertiesDiff: &diff.SchemaDiff{
+				AnyOfDiff: &diff.SchemaListDiff{
+					Modified: diff.ModifiedSchemas{
+						"#1": {TypeDiff: &diff.ValueDiff{From: "string", To: "integer"}},
+					},
+				},
+			}},
+			expected: strings.Join([]string{
+				"~ changed additional properties:",
+				"  ~ changed anyOf subschema #1:",
+				"    ~ changed type from string to integer",
+			}, "\n"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			printer := indent.NewIndenter()
+			printSchemaDiff(tc.diff, printer)
+
+			output := strings.TrimRight(printer.String(), "\n")
+			if output != tc.expected {
+				t.Errorf("Expected\n\n%s\n\nbut got\n\n%s", tc.expected, output)
+			}
+
+			// ensure no Go-syntax dumps or pointer addresses slip through
+			for _, s := range []string{"&diff.", "0x", "%!"} {
+				if strings.Contains(output, s) {
+					t.Errorf("Output contains %q:\n%s", s, output)
+				}
+			}
+		})
+	}
+}
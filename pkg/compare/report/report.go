@@ -4,13 +4,32 @@
 package report
 
 import (
+	"github.com/tufin/oasdiff/checker"
+
 	"go.xrstf.de/crdiff/pkg/compare"
+	"go.xrstf.de/crdiff/pkg/crd"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
 // Report is the result of one execution of crdiff.
 // It contains the diffs for every found CRDs.
 type Report struct {
-	Diffs map[string]compare.CRDDiff `json:"diffs"`
+	Diffs     map[string]compare.CRDDiff `json:"diffs"`
+	AddedCRDs map[string]AddedCRD        `json:"added,omitempty"`
+
+	// Sources optionally contains the source location of every CRD, which
+	// is the revision for changed and added CRDs and the base for removed
+	// CRDs.
+	Sources map[string]*crd.Source `json:"-"`
+}
+
+// AddedCRD describes a CRD that only exists in the revision
+// and not in the base. Adding CRDs is never a breaking change.
+type AddedCRD struct {
+	Scope    string                                        `json:"scope" yaml:"scope"`
+	Versions []string                                      `json:"versions" yaml:"versions"`
+	Names    apiextensionsv1.CustomResourceDefinitionNames `json:"names" yaml:"names"`
 }
 
 func (r *Report) HasChanges() bool {
@@ -18,6 +37,10 @@ func (r *Report) HasChanges() bool {
 		return false
 	}
 
+	if len(r.AddedCRDs) > 0 {
+		return true
+	}
+
 	for _, change := range r.Diffs {
 		if change.HasChanges() {
 			return true
@@ -26,3 +49,71 @@ func (r *Report) HasChanges() bool {
 
 	return false
 }
+
+// HighestLevel returns the most severe level of all breaking changes
+// in this report, or 0 if there are no breaking changes.
+func (r *Report) HighestLevel() checker.Level {
+	if r == nil {
+		return 0
+	}
+
+	var level checker.Level
+
+	for _, crdDiff := range r.Diffs {
+		if l := crdDiff.HighestLevel(); l > level {
+			level = l
+		}
+	}
+
+	return level
+}
+
+// OnlyBreaking returns a copy of the report that only contains breaking
+// changes. Added CRDs are never breaking and are left out.
+func (r *Report) OnlyBreaking() *Report {
+	result := &Report{
+		Diffs:     map[string]compare.CRDDiff{},
+		AddedCRDs: map[string]AddedCRD{},
+		Sources:   r.Sources,
+	}
+
+	for crdIdentifier, crdDiff := range r.Diffs {
+		if filtered := crdDiff.OnlyBreaking(); filtered.HasBreakingChanges() {
+			result.Diffs[crdIdentifier] = filtered
+		}
+	}
+
+	return result
+}
+
+// Location is a position in the file a CRD was loaded from.
+type Location struct {
+	Filename string
+	Document int
+	Line     int
+}
+
+// Locate returns the location of the schema node at path in the given
+// version of a CRD, or nil if the CRD's source is unknown. An empty path
+// refers to the version and an empty version to the CRD itself.
+func (r *Report) Locate(crdIdentifier string, version string, path string) *Location {
+	if r == nil {
+		return nil
+	}
+
+	source := r.Sources[crdIdentifier]
+	if source == nil {
+		return nil
+	}
+
+	line := source.Line
+	if version != "" {
+		line = source.SchemaLine(version, path)
+	}
+
+	return &Location{
+		Filename: source.Filename,
+		Document: source.Document,
+		Line:     line,
+	}
+}
@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tufin/oasdiff/checker"
+	"github.com/tufin/oasdiff/diff"
+	"github.com/tufin/oasdiff/utils"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+	"go.xrstf.de/crdiff/pkg/compare/oasdiff"
+	"go.xrstf.de/crdiff/pkg/crd"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// testReport returns a report with an added, a removed, a changed and a
+// CRD with only compatible changes.
+func testReport() *Report {
+	return &Report{
+		AddedCRDs: map[string]AddedCRD{
+			"example.com/Added": {
+				Scope:    "Namespaced",
+				Versions: []string{"v1"},
+				Names:    apiextensionsv1.CustomResourceDefinitionNames{Kind: "Added", Plural: "addeds"},
+			},
+		},
+		Diffs: map[string]compare.CRDDiff{
+			"example.com/Removed": compare.RemovedCRDDiff(),
+			"example.com/Thing": {
+				General: []compare.Change{
+					{Description: "short names changed"},
+				},
+				AddedVersions:   utils.StringList{"v2"},
+				DeletedVersions: utils.StringList{"v1alpha1"},
+				ChangedVersions: map[string]compare.CRDVersionDiff{
+					"v1": {
+						General: []compare.Change{
+							{Breaking: true, Level: checker.WARN, Description: "version is no longer served"},
+							{Description: "version is now deprecated"},
+						},
+						SchemaChanges: map[string]compare.CRDSchemaDiff{
+							".spec": {AddedProperties: utils.StringList{"foo"}},
+						},
+						PrinterColumns: &compare.PrinterColumnsDiff{
+							Added:  []compare.PrinterColumn{{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"}},
+							Broken: []compare.PrinterColumn{{Name: "Ready", Type: "string", JSONPath: ".status.ready"}},
+						},
+						BreakingChanges: []compare.BreakingChange{{
+							ID:      "request-property-max-length-decreased",
+							Level:   checker.ERR,
+							Details: &oasdiff.PropertyMaxLengthDecreasedMessage{Path: ".spec.name", Length: 15},
+						}},
+					},
+					"v1beta1": {
+						SchemaChanges: map[string]compare.CRDSchemaDiff{
+							".spec.name": {Diff: &diff.SchemaDiff{DescriptionDiff: &diff.ValueDiff{From: "old", To: "new"}}},
+						},
+					},
+				},
+			},
+			"example.com/Compatible": {
+				AddedVersions: utils.StringList{"v2"},
+			},
+		},
+		Sources: map[string]*crd.Source{
+			"example.com/Removed": {Filename: "base/removed.yaml", Document: 1, Line: 1},
+			"example.com/Thing": {
+				Filename: "crds/thing.yaml",
+				Document: 2,
+				Line:     10,
+				SchemaLines: map[string]map[string]int{
+					"v1": {"": 20, ".spec": 30, ".spec.name": 40},
+				},
+			},
+		},
+	}
+}
+
+func TestOnlyBreaking(t *testing.T) {
+	r := testReport()
+	filtered := r.OnlyBreaking()
+
+	if len(filtered.AddedCRDs) > 0 {
+		t.Errorf("Expected no added CRDs, but got %v.", sets.List(sets.KeySet(filtered.AddedCRDs)))
+	}
+
+	expectedCRDs := sets.New("example.com/Removed", "example.com/Thing")
+	if crds := sets.KeySet(filtered.Diffs); !crds.Equal(expectedCRDs) {
+		t.Fatalf("Expected CRDs %v, but got %v.", sets.List(expectedCRDs), sets.List(crds))
+	}
+
+	if !filtered.Diffs["example.com/Removed"].Removed {
+		t.Error("Expected removed CRD to still be marked as removed.")
+	}
+
+	thing := filtered.Diffs["example.com/Thing"]
+	if len(thing.General) > 0 || len(thing.AddedVersions) > 0 {
+		t.Errorf("Expected no compatible CRD-wide changes, but got %+v.", thing)
+	}
+
+	if len(thing.DeletedVersions) != 1 {
+		t.Errorf("Expected the deleted version to be kept, but got %v.", thing.DeletedVersions)
+	}
+
+	if versions := sets.KeySet(thing.ChangedVersions); !versions.Equal(sets.New("v1")) {
+		t.Fatalf("Expected only v1 to remain, but got %v.", sets.List(versions))
+	}
+
+	v1 := thing.ChangedVersions["v1"]
+	if len(v1.General) != 1 || !v1.General[0].Breaking {
+		t.Errorf("Expected only the breaking general change, but got %+v.", v1.General)
+	}
+
+	if len(v1.SchemaChanges) > 0 {
+		t.Errorf("Expected no schema changes, but got %+v.", v1.SchemaChanges)
+	}
+
+	if columns := v1.PrinterColumns; columns == nil || len(columns.Added) > 0 || len(columns.Broken) != 1 {
+		t.Errorf("Expected only the broken printer column, but got %+v.", columns)
+	}
+
+	if len(v1.BreakingChanges) != 1 {
+		t.Errorf("Expected the breaking change to be kept, but got %+v.", v1.BreakingChanges)
+	}
+
+	if filtered.HighestLevel() != r.HighestLevel() {
+		t.Errorf("Expected highest level %v, but got %v.", r.HighestLevel(), filtered.HighestLevel())
+	}
+
+	// the original report must not be modified
+	if len(r.Diffs["example.com/Thing"].ChangedVersions["v1"].SchemaChanges) == 0 {
+		t.Error("Filtering modified the original report.")
+	}
+}
+
+func compareGoldenFile(t *testing.T, filename string, actual []byte) {
+	t.Helper()
+
+	expected, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+
+	if strings.TrimSpace(string(expected)) != strings.TrimSpace(string(actual)) {
+		t.Errorf("Output does not match %s.\n\nExpected:\n\n%s\n\nActual:\n\n%s", filename, expected, actual)
+	}
+}
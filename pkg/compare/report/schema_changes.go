@@ -0,0 +1,385 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/tufin/oasdiff/diff"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+	"go.xrstf.de/crdiff/pkg/crd"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// These are the actions of a SchemaChange to a list-like attribute.
+const (
+	ActionAdded   = "added"
+	ActionRemoved = "removed"
+	ActionChanged = "changed"
+
+	// actionSet is only used for displaying value changes.
+	actionSet = "set"
+)
+
+// SchemaChange is a single change to one attribute of a schema node. It is
+// the common ground for output formats that present schema changes as a
+// flat list instead of sentences.
+type SchemaChange struct {
+	// Path is the schema path of the changed node. Changes to properties
+	// of a node (added, removed, required or pruned properties) point to
+	// the property itself.
+	Path string
+	// Subschema locates nested schemas below Path as a JSON pointer
+	// (e.g. "anyOf/1/not"); it is empty for changes to the node itself.
+	Subschema string
+	// Attribute is named like in a CRD's OpenAPI schema (e.g. "maxLength"
+	// or "validationRule"). It is empty if the entire schema was added or
+	// removed.
+	Attribute string
+	// Action is empty for plain values and otherwise one of the Action
+	// constants.
+	Action string
+	Old    interface{}
+	New    interface{}
+}
+
+// SchemaValueAttribute is a plain value attribute of an oasdiff SchemaDiff.
+type SchemaValueAttribute struct {
+	// Name is the name of the attribute in a CRD's OpenAPI schema.
+	Name string
+	// Label is a human readable name.
+	Label string
+	Field func(d *diff.SchemaDiff) **diff.ValueDiff
+}
+
+// SchemaValueAttributes are all plain value attributes of an oasdiff SchemaDiff.
+var SchemaValueAttributes = []SchemaValueAttribute{
+	{"type", "type", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.TypeDiff }},
+	{"title", "title", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.TitleDiff }},
+	{"format", "format", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.FormatDiff }},
+	{"description", "description", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.DescriptionDiff }},
+	{"default", "default value", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.DefaultDiff }},
+	{"example", "example", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.ExampleDiff }},
+	{"additionalPropertiesAllowed", "additional properties allowed", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.AdditionalPropertiesAllowedDiff }},
+	{"uniqueItems", "unique items", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.UniqueItemsDiff }},
+	{"exclusiveMinimum", "exclusive minimum", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.ExclusiveMinDiff }},
+	{"exclusiveMaximum", "exclusive maximum", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.ExclusiveMaxDiff }},
+	{"nullable", "nullable", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.NullableDiff }},
+	{"readOnly", "read only", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.ReadOnlyDiff }},
+	{"writeOnly", "write only", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.WriteOnlyDiff }},
+	{"allowEmptyValue", "allow empty value", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.AllowEmptyValueDiff }},
+	{"xml", "XML", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.XMLDiff }},
+	{"deprecated", "deprecated", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.DeprecatedDiff }},
+	{"minimum", "minimum allowed value", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.MinDiff }},
+	{"maximum", "maximum allowed value", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.MaxDiff }},
+	{"multipleOf", "multiple of", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.MultipleOfDiff }},
+	{"minLength", "minimum required length", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.MinLengthDiff }},
+	{"maxLength", "maximum allowed length", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.MaxLengthDiff }},
+	{"pattern", "pattern", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.PatternDiff }},
+	{"minItems", "minimum required items", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.MinItemsDiff }},
+	{"maxItems", "maximum allowed items", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.MaxItemsDiff }},
+	{"minProperties", "minimum required properties", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.MinPropsDiff }},
+	{"maxProperties", "maximum allowed properties", func(d *diff.SchemaDiff) **diff.ValueDiff { return &d.MaxPropsDiff }},
+}
+
+// SchemaComposition is a list of schemas in an oasdiff SchemaDiff.
+type SchemaComposition struct {
+	Name  string
+	Field func(d *diff.SchemaDiff) **diff.SchemaListDiff
+}
+
+// SchemaCompositions are all schema lists in an oasdiff SchemaDiff.
+var SchemaCompositions = []SchemaComposition{
+	{"anyOf", func(d *diff.SchemaDiff) **diff.SchemaListDiff { return &d.AnyOfDiff }},
+	{"oneOf", func(d *diff.SchemaDiff) **diff.SchemaListDiff { return &d.OneOfDiff }},
+	{"allOf", func(d *diff.SchemaDiff) **diff.SchemaListDiff { return &d.AllOfDiff }},
+}
+
+// CollectSchemaChanges flattens the changes of a single schema node at the
+// given path, including all nested schemas, into a list. The order of the
+// changes is stable.
+func CollectSchemaChanges(path string, d *compare.CRDSchemaDiff) []SchemaChange {
+	c := &schemaChangeCollector{path: path, changes: []SchemaChange{}}
+
+	for _, name := range d.AddedProperties {
+		c.addAt(crd.ChildPath(path, name), "property", ActionAdded, nil, name)
+	}
+
+	for _, name := range d.DeletedProperties {
+		c.addAt(crd.ChildPath(path, name), "property", ActionRemoved, name, nil)
+	}
+
+	if d.Diff != nil {
+		c.collectSchemaDiff("", d.Diff)
+	}
+
+	if lt := d.ListType; lt != nil {
+		c.add("", "listType", "", lt.From, lt.To)
+	}
+
+	if keys := d.ListMapKeys; keys != nil {
+		c.add("", "listMapKeys", "", keys.From, keys.To)
+	}
+
+	if rules := d.ValidationRules; rules != nil {
+		for _, rule := range rules.Added {
+			c.add("", "validationRule", ActionAdded, nil, rule)
+		}
+
+		for _, rule := range rules.Removed {
+			c.add("", "validationRule", ActionRemoved, rule, nil)
+		}
+
+		for _, change := range rules.Modified {
+			c.add("", "validationRule", ActionChanged, change.From, change.To)
+		}
+	}
+
+	if puf := d.PreserveUnknownFields; puf != nil {
+		c.add("", "preserveUnknownFields", "", puf.From, puf.To)
+	}
+
+	for _, name := range d.PrunedProperties {
+		c.addAt(crd.ChildPath(path, name), "prunedProperty", "", nil, name)
+	}
+
+	if er := d.EmbeddedResource; er != nil {
+		c.add("", "embeddedResource", "", er.From, er.To)
+	}
+
+	if mt := d.MapType; mt != nil {
+		c.add("", "mapType", "", mt.From, mt.To)
+	}
+
+	return c.changes
+}
+
+type schemaChangeCollector struct {
+	path    string
+	changes []SchemaChange
+}
+
+func (c *schemaChangeCollector) add(subschema, attribute, action string, oldValue, newValue interface{}) {
+	c.changes = append(c.changes, SchemaChange{
+		Path:      c.path,
+		Subschema: subschema,
+		Attribute: attribute,
+		Action:    action,
+		Old:       oldValue,
+		New:       newValue,
+	})
+}
+
+func (c *schemaChangeCollector) addAt(path, attribute, action string, oldValue, newValue interface{}) {
+	c.changes = append(c.changes, SchemaChange{
+		Path:      path,
+		Attribute: attribute,
+		Action:    action,
+		Old:       oldValue,
+		New:       newValue,
+	})
+}
+
+// addChild records a change that refers to a named child of the current
+// schema (a property or composed schema). Properties of the node itself
+// are located by their path, all others by their subschema.
+func (c *schemaChangeCollector) addChild(subschema, list, name, attribute, action string, oldValue, newValue interface{}) {
+	if subschema == "" && list == "properties" {
+		c.addAt(crd.ChildPath(c.path, name), attribute, action, oldValue, newValue)
+	} else {
+		c.add(JoinSubschema(subschema, list, name), attribute, action, oldValue, newValue)
+	}
+}
+
+// collectSchemaDiff collects the changes of an oasdiff SchemaDiff. Diffs of
+// nested schemas are only found inside compositions or "not" and are
+// located via subschema.
+func (c *schemaChangeCollector) collectSchemaDiff(subschema string, d *diff.SchemaDiff) {
+	if d.SchemaAdded {
+		c.add(subschema, "", ActionAdded, nil, nil)
+	}
+
+	if d.SchemaDeleted {
+		c.add(subschema, "", ActionRemoved, nil, nil)
+	}
+
+	if d.CircularRefDiff {
+		c.add(subschema, "circularRef", "", nil, nil)
+	}
+
+	// CRD schemas have no discriminator and neither engine diffs extensions,
+	// so these are passed on as the raw oasdiff diffs just in case
+	if d.ExtensionsDiff != nil {
+		c.add(subschema, "extensions", "", nil, d.ExtensionsDiff)
+	}
+
+	if d.DiscriminatorDiff != nil {
+		c.add(subschema, "discriminator", "", nil, d.DiscriminatorDiff)
+	}
+
+	if dd := d.ExternalDocsDiff; dd != nil {
+		if dd.Added {
+			c.add(subschema, "externalDocs", ActionAdded, nil, nil)
+		}
+
+		if dd.Deleted {
+			c.add(subschema, "externalDocs", ActionRemoved, nil, nil)
+		}
+
+		if vd := dd.DescriptionDiff; vd != nil {
+			c.add(subschema, "externalDocs.description", "", vd.From, vd.To)
+		}
+
+		if vd := dd.URLDiff; vd != nil {
+			c.add(subschema, "externalDocs.url", "", vd.From, vd.To)
+		}
+	}
+
+	for _, attr := range SchemaValueAttributes {
+		if vd := *attr.Field(d); vd != nil {
+			c.add(subschema, attr.Name, "", vd.From, vd.To)
+		}
+	}
+
+	if ed := d.EnumDiff; ed != nil {
+		if ed.EnumAdded {
+			c.add(subschema, "enum", ActionAdded, nil, nil)
+		}
+
+		if ed.EnumDeleted {
+			c.add(subschema, "enum", ActionRemoved, nil, nil)
+		}
+
+		for _, value := range ed.Added {
+			c.add(subschema, "enum.value", ActionAdded, nil, value)
+		}
+
+		for _, value := range ed.Deleted {
+			c.add(subschema, "enum.value", ActionRemoved, value, nil)
+		}
+	}
+
+	if rd := d.RequiredDiff; rd != nil {
+		for _, name := range rd.Added {
+			c.addChild(subschema, "properties", name, "required", ActionAdded, nil, name)
+		}
+
+		for _, name := range rd.Deleted {
+			c.addChild(subschema, "properties", name, "required", ActionRemoved, name, nil)
+		}
+	}
+
+	if pd := d.PropertiesDiff; pd != nil {
+		for _, name := range sortedStrings(pd.Added) {
+			c.addChild(subschema, "properties", name, "property", ActionAdded, nil, name)
+		}
+
+		for _, name := range sortedStrings(pd.Deleted) {
+			c.addChild(subschema, "properties", name, "property", ActionRemoved, name, nil)
+		}
+
+		for _, name := range sets.List(sets.KeySet(pd.Modified)) {
+			c.collectSchemaDiff(JoinSubschema(subschema, "properties", name), pd.Modified[name])
+		}
+	}
+
+	for _, comp := range SchemaCompositions {
+		ld := *comp.Field(d)
+		if ld == nil {
+			continue
+		}
+
+		for _, name := range sortedStrings(ld.Added) {
+			c.addChild(subschema, comp.Name, name, comp.Name, ActionAdded, nil, name)
+		}
+
+		for _, name := range sortedStrings(ld.Deleted) {
+			c.addChild(subschema, comp.Name, name, comp.Name, ActionRemoved, name, nil)
+		}
+
+		for _, name := range sets.List(sets.KeySet(ld.Modified)) {
+			c.collectSchemaDiff(JoinSubschema(subschema, comp.Name, name), ld.Modified[name])
+		}
+	}
+
+	if d.NotDiff != nil {
+		c.collectSchemaDiff(JoinSubschema(subschema, "not"), d.NotDiff)
+	}
+
+	if d.ItemsDiff != nil {
+		c.collectSchemaDiff(JoinSubschema(subschema, "items"), d.ItemsDiff)
+	}
+
+	if d.AdditionalPropertiesDiff != nil {
+		c.collectSchemaDiff(JoinSubschema(subschema, "additionalProperties"), d.AdditionalPropertiesDiff)
+	}
+}
+
+var subschemaEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// JoinSubschema appends segments to a subschema JSON pointer, escaping
+// them as needed.
+func JoinSubschema(subschema string, segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, s := range segments {
+		escaped[i] = subschemaEscaper.Replace(s)
+	}
+
+	if subschema != "" {
+		escaped = append([]string{subschema}, escaped...)
+	}
+
+	return strings.Join(escaped, "/")
+}
+
+// valueAction determines whether a value was set, removed or changed.
+func valueAction(from, to interface{}) string {
+	switch {
+	case isEmpty(formatValue(from)):
+		return actionSet
+	case isEmpty(formatValue(to)):
+		return ActionRemoved
+	default:
+		return ActionChanged
+	}
+}
+
+// formatValue turns a schema value into a string. Complex values like
+// default objects are encoded as JSON.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool, int, int32, int64, uint64, float64:
+		return fmt.Sprintf("%v", v)
+	}
+
+	// oasdiff uses pointers for optional values like maxLength
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ""
+		}
+
+		return formatValue(rv.Elem().Interface())
+	}
+
+	// do not escape characters like ">" in validation rules
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err == nil {
+		return strings.TrimSuffix(buf.String(), "\n")
+	}
+
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,720 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/tufin/oasdiff/checker"
+	"github.com/tufin/oasdiff/diff"
+	"github.com/tufin/oasdiff/utils"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+	"go.xrstf.de/crdiff/pkg/compare/report"
+	crdpkg "go.xrstf.de/crdiff/pkg/crd"
+)
+
+// Decode reads a versioned report and converts it into a report.Report.
+func Decode(r io.Reader) (*report.Report, error) {
+	decoder := json.NewDecoder(r)
+	// keep numbers as they are instead of turning them into floats
+	decoder.UseNumber()
+
+	var rep Report
+	if err := decoder.Decode(&rep); err != nil {
+		return nil, fmt.Errorf("failed to decode report: %w", err)
+	}
+
+	return ToReport(&rep)
+}
+
+// ToReport converts a versioned report back into a report.Report.
+func ToReport(r *Report) (*report.Report, error) {
+	if r.APIVersion != APIVersion {
+		return nil, fmt.Errorf("unsupported apiVersion %q, expected %q", r.APIVersion, APIVersion)
+	}
+
+	result := &report.Report{
+		Diffs:     map[string]compare.CRDDiff{},
+		AddedCRDs: map[string]report.AddedCRD{},
+		Sources:   map[string]*crdpkg.Source{},
+	}
+
+	for _, crd := range r.CRDs {
+		if source := decodeSource(crd); source != nil {
+			result.Sources[crd.Name] = source
+		}
+
+		if len(crd.Changes) > 0 && crd.Changes[0].Kind == KindCRDAdded {
+			var added report.AddedCRD
+			if err := convert(crd.Changes[0].New, &added); err != nil {
+				return nil, fmt.Errorf("invalid %s change for %s: %w", KindCRDAdded, crd.Name, err)
+			}
+
+			result.AddedCRDs[crd.Name] = added
+			continue
+		}
+
+		if len(crd.Changes) > 0 && crd.Changes[0].Kind == KindCRDRemoved {
+			result.Diffs[crd.Name] = compare.RemovedCRDDiff()
+			continue
+		}
+
+		crdDiff := compare.CRDDiff{
+			General:         []compare.Change{},
+			AddedVersions:   utils.StringList{},
+			DeletedVersions: utils.StringList{},
+			ChangedVersions: map[string]compare.CRDVersionDiff{},
+		}
+
+		for i, change := range crd.Changes {
+			if err := decodeChange(&crdDiff, change); err != nil {
+				return nil, fmt.Errorf("invalid change #%d for %s: %w", i, crd.Name, err)
+			}
+		}
+
+		result.Diffs[crd.Name] = crdDiff
+	}
+
+	return result, nil
+}
+
+// decodeSource reconstructs the source of a CRD from the locations of its
+// changes; only the lines of changed schema nodes are known.
+func decodeSource(c CRD) *crdpkg.Source {
+	var source *crdpkg.Source
+
+	for _, change := range c.Changes {
+		loc := change.Location
+		if loc == nil {
+			continue
+		}
+
+		if source == nil {
+			source = &crdpkg.Source{
+				Filename:    loc.File,
+				Document:    loc.Document,
+				SchemaLines: map[string]map[string]int{},
+			}
+		}
+
+		if change.Version == "" {
+			source.Line = loc.Line
+			continue
+		}
+
+		lines, exists := source.SchemaLines[change.Version]
+		if !exists {
+			lines = map[string]int{}
+			source.SchemaLines[change.Version] = lines
+		}
+
+		lines[locationPath(change)] = loc.Line
+	}
+
+	return source
+}
+
+func decodeChange(d *compare.CRDDiff, c Change) error {
+	switch c.Kind {
+	case KindVersionAdded:
+		d.AddedVersions = append(d.AddedVersions, c.Version)
+		return nil
+
+	case KindVersionRemoved:
+		d.DeletedVersions = append(d.DeletedVersions, c.Version)
+		return nil
+
+	case KindGeneral:
+		if c.Version == "" {
+			d.General = append(d.General, toChange(c))
+			return nil
+		}
+	}
+
+	if c.Version == "" {
+		return fmt.Errorf("%s change has no version", c.Kind)
+	}
+
+	versionDiff, exists := d.ChangedVersions[c.Version]
+	if !exists {
+		versionDiff = compare.CRDVersionDiff{
+			General:         []compare.Change{},
+			SchemaChanges:   map[string]compare.CRDSchemaDiff{},
+			BreakingChanges: []compare.BreakingChange{},
+		}
+	}
+
+	if err := decodeVersionChange(&versionDiff, c); err != nil {
+		return err
+	}
+
+	d.ChangedVersions[c.Version] = versionDiff
+
+	return nil
+}
+
+func decodeVersionChange(d *compare.CRDVersionDiff, c Change) error {
+	switch {
+	case c.Kind == KindGeneral:
+		d.General = append(d.General, toChange(c))
+		return nil
+
+	case c.Kind == KindBreaking:
+		details := compare.NewBreakingChangeDetails(c.ID)
+		if err := convert(c.Details, details); err != nil {
+			return fmt.Errorf("invalid details: %w", err)
+		}
+
+		d.BreakingChanges = append(d.BreakingChanges, compare.BreakingChange{
+			ID:      c.ID,
+			Level:   toLevel(c.Severity),
+			Details: details,
+		})
+		return nil
+
+	case strings.HasPrefix(c.Kind, "printerColumn."):
+		if d.PrinterColumns == nil {
+			d.PrinterColumns = &compare.PrinterColumnsDiff{}
+		}
+
+		return decodePrinterColumnChange(d.PrinterColumns, c)
+
+	case strings.HasPrefix(c.Kind, schemaAttributeKindPrefix):
+		return decodeSchemaChange(d.SchemaChanges, c)
+	}
+
+	return fmt.Errorf("unknown kind %q", c.Kind)
+}
+
+func decodePrinterColumnChange(d *compare.PrinterColumnsDiff, c Change) error {
+	var base, revision compare.PrinterColumn
+
+	if err := convert(c.Old, &base); err != nil {
+		return fmt.Errorf("invalid old value: %w", err)
+	}
+
+	if err := convert(c.New, &revision); err != nil {
+		return fmt.Errorf("invalid new value: %w", err)
+	}
+
+	switch c.Kind {
+	case KindPrinterColumnAdded:
+		d.Added = append(d.Added, revision)
+	case KindPrinterColumnRemoved:
+		d.Removed = append(d.Removed, base)
+	case KindPrinterColumnChanged:
+		d.Modified = append(d.Modified, compare.PrinterColumnChange{Name: base.Name, Base: base, Revision: revision})
+	case KindPrinterColumnBroken:
+		d.Broken = append(d.Broken, revision)
+	default:
+		return fmt.Errorf("unknown kind %q", c.Kind)
+	}
+
+	return nil
+}
+
+func decodeSchemaChange(changes map[string]compare.CRDSchemaDiff, c Change) error {
+	path := c.Path
+
+	// these changes point to the property instead of its parent
+	var name string
+	if c.Subschema == "" && isPropertyKind(c.Kind) {
+		name = propertyName(c)
+		if name == "" {
+			return fmt.Errorf("%s change has no name", c.Kind)
+		}
+
+		path = parentPath(path, name)
+	}
+
+	schemaDiff := changes[path]
+	defer func() {
+		changes[path] = schemaDiff
+	}()
+
+	switch c.Kind {
+	case KindListType:
+		change := &compare.StringChange{}
+		schemaDiff.ListType = change
+		return convertChange(c, &change.From, &change.To)
+
+	case KindListMapKeys:
+		change := &compare.StringListChange{}
+		schemaDiff.ListMapKeys = change
+		return convertChange(c, &change.From, &change.To)
+
+	case KindValidationRuleAdded, KindValidationRuleRemoved, KindValidationRuleChanged:
+		if schemaDiff.ValidationRules == nil {
+			schemaDiff.ValidationRules = &compare.ValidationRulesDiff{}
+		}
+
+		return decodeValidationRuleChange(schemaDiff.ValidationRules, c)
+
+	case KindPreserveUnknownFields:
+		change := &compare.BoolChange{}
+		schemaDiff.PreserveUnknownFields = change
+		return convertChange(c, &change.From, &change.To)
+
+	case KindPrunedProperty:
+		schemaDiff.PrunedProperties = append(schemaDiff.PrunedProperties, name)
+		return nil
+
+	case KindEmbeddedResource:
+		change := &compare.BoolChange{}
+		schemaDiff.EmbeddedResource = change
+		return convertChange(c, &change.From, &change.To)
+
+	case KindMapType:
+		change := &compare.StringChange{}
+		schemaDiff.MapType = change
+		return convertChange(c, &change.From, &change.To)
+
+	case KindPropertyAdded:
+		if c.Subschema == "" {
+			schemaDiff.AddedProperties = append(schemaDiff.AddedProperties, name)
+			return nil
+		}
+
+	case KindPropertyRemoved:
+		if c.Subschema == "" {
+			schemaDiff.DeletedProperties = append(schemaDiff.DeletedProperties, name)
+			return nil
+		}
+	}
+
+	if schemaDiff.Diff == nil {
+		schemaDiff.Diff = &diff.SchemaDiff{}
+	}
+
+	return decodeSchemaDiffChange(schemaDiff.Diff, c)
+}
+
+func decodeValidationRuleChange(d *compare.ValidationRulesDiff, c Change) error {
+	var from, to compare.ValidationRule
+
+	if err := convertChange(c, &from, &to); err != nil {
+		return err
+	}
+
+	switch c.Kind {
+	case KindValidationRuleAdded:
+		d.Added = append(d.Added, to)
+	case KindValidationRuleRemoved:
+		d.Removed = append(d.Removed, from)
+	default:
+		d.Modified = append(d.Modified, compare.ValidationRuleChange{From: from, To: to})
+	}
+
+	return nil
+}
+
+// decodeSchemaDiffChange applies a single change to an oasdiff SchemaDiff,
+// creating nested diffs for the change's subschema as needed.
+func decodeSchemaDiffChange(d *diff.SchemaDiff, c Change) error {
+	segments := splitSegments(c.Subschema)
+
+	// changes to items of lists point to the item itself
+	var name string
+	if len(segments) >= 2 && (isPropertyKind(c.Kind) || isCompositionKind(c.Kind)) {
+		name = segments[len(segments)-1]
+		segments = segments[:len(segments)-2]
+	}
+
+	d, err := nestedSchemaDiff(d, segments)
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = propertyName(c)
+	}
+
+	if name == "" && (isPropertyKind(c.Kind) || isCompositionKind(c.Kind)) {
+		return fmt.Errorf("%s change has no name", c.Kind)
+	}
+
+	switch c.Kind {
+	case KindSchemaAdded:
+		d.SchemaAdded = true
+	case KindSchemaRemoved:
+		d.SchemaDeleted = true
+	case KindCircularRef:
+		d.CircularRefDiff = true
+
+	case KindExternalDocsAdded, KindExternalDocsRemoved, KindExternalDocsDescription, KindExternalDocsURL:
+		if d.ExternalDocsDiff == nil {
+			d.ExternalDocsDiff = &diff.ExternalDocsDiff{}
+		}
+
+		switch c.Kind {
+		case KindExternalDocsAdded:
+			d.ExternalDocsDiff.Added = true
+		case KindExternalDocsRemoved:
+			d.ExternalDocsDiff.Deleted = true
+		case KindExternalDocsDescription:
+			return decodeStringValueDiff(&d.ExternalDocsDiff.DescriptionDiff, c)
+		case KindExternalDocsURL:
+			return decodeStringValueDiff(&d.ExternalDocsDiff.URLDiff, c)
+		}
+
+	case KindEnumAdded, KindEnumRemoved, KindEnumValueAdded, KindEnumValueRemoved:
+		if d.EnumDiff == nil {
+			d.EnumDiff = &diff.EnumDiff{}
+		}
+
+		switch c.Kind {
+		case KindEnumAdded:
+			d.EnumDiff.EnumAdded = true
+		case KindEnumRemoved:
+			d.EnumDiff.EnumDeleted = true
+		case KindEnumValueAdded:
+			d.EnumDiff.Added = append(d.EnumDiff.Added, c.New)
+		case KindEnumValueRemoved:
+			d.EnumDiff.Deleted = append(d.EnumDiff.Deleted, c.Old)
+		}
+
+	case KindRequiredAdded, KindRequiredRemoved:
+		if d.RequiredDiff == nil {
+			d.RequiredDiff = &diff.RequiredPropertiesDiff{}
+		}
+
+		if c.Kind == KindRequiredAdded {
+			d.RequiredDiff.Added = append(d.RequiredDiff.Added, name)
+		} else {
+			d.RequiredDiff.Deleted = append(d.RequiredDiff.Deleted, name)
+		}
+
+	case KindPropertyAdded, KindPropertyRemoved:
+		if d.PropertiesDiff == nil {
+			d.PropertiesDiff = &diff.SchemasDiff{}
+		}
+
+		if c.Kind == KindPropertyAdded {
+			d.PropertiesDiff.Added = append(d.PropertiesDiff.Added, name)
+		} else {
+			d.PropertiesDiff.Deleted = append(d.PropertiesDiff.Deleted, name)
+		}
+
+	default:
+		for _, comp := range report.SchemaCompositions {
+			field := comp.Field(d)
+
+			switch c.Kind {
+			case schemaAttributeKindPrefix + comp.Name + ".added":
+				if *field == nil {
+					*field = &diff.SchemaListDiff{}
+				}
+				(*field).Added = append((*field).Added, name)
+				return nil
+
+			case schemaAttributeKindPrefix + comp.Name + ".removed":
+				if *field == nil {
+					*field = &diff.SchemaListDiff{}
+				}
+				(*field).Deleted = append((*field).Deleted, name)
+				return nil
+			}
+		}
+
+		for _, attr := range report.SchemaValueAttributes {
+			if c.Kind == schemaAttributeKindPrefix+attr.Name {
+				value := valueTypes[attr.Name]
+
+				if !value.matches(c.Old) {
+					return fmt.Errorf("invalid old value: expected %s, got %v", value, c.Old)
+				}
+
+				if !value.matches(c.New) {
+					return fmt.Errorf("invalid new value: expected %s, got %v", value, c.New)
+				}
+
+				*attr.Field(d) = &diff.ValueDiff{From: c.Old, To: c.New}
+				return nil
+			}
+		}
+
+		return fmt.Errorf("unknown kind %q", c.Kind)
+	}
+
+	return nil
+}
+
+func decodeStringValueDiff(vd **diff.ValueDiff, c Change) error {
+	if !stringValue.matches(c.Old) {
+		return fmt.Errorf("invalid old value: expected %s, got %v", stringValue, c.Old)
+	}
+
+	if !stringValue.matches(c.New) {
+		return fmt.Errorf("invalid new value: expected %s, got %v", stringValue, c.New)
+	}
+
+	*vd = &diff.ValueDiff{From: c.Old, To: c.New}
+
+	return nil
+}
+
+func nestedSchemaDiff(d *diff.SchemaDiff, segments []string) (*diff.SchemaDiff, error) {
+	for len(segments) > 0 {
+		segment := segments[0]
+		segments = segments[1:]
+
+		switch segment {
+		case "not":
+			if d.NotDiff == nil {
+				d.NotDiff = &diff.SchemaDiff{}
+			}
+			d = d.NotDiff
+
+		case "items":
+			if d.ItemsDiff == nil {
+				d.ItemsDiff = &diff.SchemaDiff{}
+			}
+			d = d.ItemsDiff
+
+		case "additionalProperties":
+			if d.AdditionalPropertiesDiff == nil {
+				d.AdditionalPropertiesDiff = &diff.SchemaDiff{}
+			}
+			d = d.AdditionalPropertiesDiff
+
+		case "properties":
+			if len(segments) == 0 {
+				return nil, fmt.Errorf("subschema %q is missing a property name", segment)
+			}
+
+			if d.PropertiesDiff == nil {
+				d.PropertiesDiff = &diff.SchemasDiff{}
+			}
+			if d.PropertiesDiff.Modified == nil {
+				d.PropertiesDiff.Modified = diff.ModifiedSchemas{}
+			}
+
+			d = nestedModifiedSchema(d.PropertiesDiff.Modified, segments[0])
+			segments = segments[1:]
+
+		default:
+			field := compositionField(d, segment)
+			if field == nil {
+				return nil, fmt.Errorf("unknown subschema %q", segment)
+			}
+
+			if len(segments) == 0 {
+				return nil, fmt.Errorf("subschema %q is missing a schema name", segment)
+			}
+
+			if *field == nil {
+				*field = &diff.SchemaListDiff{}
+			}
+			if (*field).Modified == nil {
+				(*field).Modified = diff.ModifiedSchemas{}
+			}
+
+			d = nestedModifiedSchema((*field).Modified, segments[0])
+			segments = segments[1:]
+		}
+	}
+
+	return d, nil
+}
+
+func nestedModifiedSchema(modified diff.ModifiedSchemas, name string) *diff.SchemaDiff {
+	if _, exists := modified[name]; !exists {
+		modified[name] = &diff.SchemaDiff{}
+	}
+
+	return modified[name]
+}
+
+func compositionField(d *diff.SchemaDiff, name string) **diff.SchemaListDiff {
+	for _, comp := range report.SchemaCompositions {
+		if comp.Name == name {
+			return comp.Field(d)
+		}
+	}
+
+	return nil
+}
+
+func isPropertyKind(kind string) bool {
+	switch kind {
+	case KindPropertyAdded, KindPropertyRemoved, KindRequiredAdded, KindRequiredRemoved, KindPrunedProperty:
+		return true
+	default:
+		return false
+	}
+}
+
+func isCompositionKind(kind string) bool {
+	for _, comp := range report.SchemaCompositions {
+		if kind == schemaAttributeKindPrefix+comp.Name+".added" || kind == schemaAttributeKindPrefix+comp.Name+".removed" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// valueType is the type of the old and new values of plain schema attributes.
+type valueType int
+
+const (
+	anyValue valueType = iota
+	stringValue
+	boolValue
+	numberValue
+	integerValue
+	objectValue
+)
+
+func (t valueType) String() string {
+	switch t {
+	case stringValue:
+		return "string"
+	case boolValue:
+		return "boolean"
+	case numberValue:
+		return "number"
+	case integerValue:
+		return "integer"
+	case objectValue:
+		return "object"
+	default:
+		return "any value"
+	}
+}
+
+// matches returns true if the value is of type t. Values can either be
+// generically decoded JSON or the Go values of a report created by FromReport.
+// A nil value means the attribute was not set and is always valid.
+func (t valueType) matches(value interface{}) bool {
+	if value == nil || t == anyValue {
+		return true
+	}
+
+	if n, ok := value.(json.Number); ok {
+		if t == integerValue {
+			_, err := n.Int64()
+			return err == nil
+		}
+
+		return t == numberValue
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return true
+		}
+
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return t == stringValue
+	case reflect.Bool:
+		return t == boolValue
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return t == integerValue || t == numberValue
+	case reflect.Float32, reflect.Float64:
+		return t == numberValue || (t == integerValue && rv.Float() == math.Trunc(rv.Float()))
+	case reflect.Map, reflect.Struct:
+		return t == objectValue
+	default:
+		return false
+	}
+}
+
+// propertyName returns the name of the property or schema that has been
+// added or removed.
+func propertyName(c Change) string {
+	if s, ok := c.New.(string); ok {
+		return s
+	}
+
+	s, _ := c.Old.(string)
+
+	return s
+}
+
+func parentPath(path string, name string) string {
+	parent := strings.TrimSuffix(path, "."+name)
+	if parent == "" {
+		return "."
+	}
+
+	return parent
+}
+
+var segmentUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func splitSegments(subschema string) []string {
+	if subschema == "" {
+		return nil
+	}
+
+	segments := strings.Split(subschema, "/")
+	for i, s := range segments {
+		segments[i] = segmentUnescaper.Replace(s)
+	}
+
+	return segments
+}
+
+func toChange(c Change) compare.Change {
+	// levels are only relevant for breaking changes
+	if c.Severity == SeverityInfo {
+		return compare.Change{Description: c.Description}
+	}
+
+	return compare.Change{
+		Breaking:    true,
+		Level:       toLevel(c.Severity),
+		Description: c.Description,
+	}
+}
+
+func toLevel(severity Severity) checker.Level {
+	switch severity {
+	case SeverityError:
+		return checker.ERR
+	case SeverityWarning:
+		return checker.WARN
+	default:
+		return checker.INFO
+	}
+}
+
+func convertChange(c Change, from, to interface{}) error {
+	if err := convert(c.Old, from); err != nil {
+		return fmt.Errorf("invalid old value: %w", err)
+	}
+
+	if err := convert(c.New, to); err != nil {
+		return fmt.Errorf("invalid new value: %w", err)
+	}
+
+	return nil
+}
+
+// convert turns a generically decoded JSON value into a concrete type.
+func convert(in interface{}, out interface{}) error {
+	if in == nil {
+		return nil
+	}
+
+	encoded, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(encoded, out)
+}
@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package v1
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/tufin/oasdiff/checker"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+	"go.xrstf.de/crdiff/pkg/compare/report"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// valueTypes are the types of the values of all plain schema attributes.
+var valueTypes = map[string]valueType{
+	"type":                        stringValue,
+	"title":                       stringValue,
+	"format":                      stringValue,
+	"description":                 stringValue,
+	"default":                     anyValue,
+	"example":                     anyValue,
+	"additionalPropertiesAllowed": boolValue,
+	"uniqueItems":                 boolValue,
+	"exclusiveMinimum":            boolValue,
+	"exclusiveMaximum":            boolValue,
+	"nullable":                    boolValue,
+	"readOnly":                    boolValue,
+	"writeOnly":                   boolValue,
+	"allowEmptyValue":             boolValue,
+	"xml":                         objectValue,
+	"deprecated":                  boolValue,
+	"minimum":                     numberValue,
+	"maximum":                     numberValue,
+	"multipleOf":                  numberValue,
+	"minLength":                   integerValue,
+	"maxLength":                   integerValue,
+	"pattern":                     stringValue,
+	"minItems":                    integerValue,
+	"maxItems":                    integerValue,
+	"minProperties":               integerValue,
+	"maxProperties":               integerValue,
+}
+
+// FromReport converts a report into the versioned format. The result is
+// deterministic: CRDs are sorted by name and changes are grouped by version
+// and sorted by path.
+func FromReport(r *report.Report) *Report {
+	result := &Report{
+		APIVersion: APIVersion,
+		CRDs:       []CRD{},
+	}
+
+	if r == nil {
+		return result
+	}
+
+	for _, name := range sets.List(sets.KeySet(r.Diffs).Union(sets.KeySet(r.AddedCRDs))) {
+		enc := &encoder{report: r, crd: name, changes: []Change{}}
+
+		if added, exists := r.AddedCRDs[name]; exists {
+			enc.add(Change{Kind: KindCRDAdded, Severity: SeverityInfo, New: added})
+		} else if crdDiff := r.Diffs[name]; crdDiff.Removed {
+			enc.add(Change{Kind: KindCRDRemoved, Severity: SeverityError})
+		} else {
+			enc.encodeCRDDiff(&crdDiff)
+		}
+
+		result.CRDs = append(result.CRDs, CRD{
+			Name:    name,
+			Changes: enc.changes,
+		})
+	}
+
+	return result
+}
+
+type encoder struct {
+	report  *report.Report
+	crd     string
+	version string
+	changes []Change
+}
+
+func (e *encoder) add(c Change) {
+	if c.Version == "" {
+		c.Version = e.version
+	}
+
+	if loc := e.report.Locate(e.crd, c.Version, locationPath(c)); loc != nil {
+		c.Location = &Location{
+			File:     loc.Filename,
+			Document: loc.Document,
+			Line:     loc.Line,
+		}
+	}
+
+	e.changes = append(e.changes, c)
+}
+
+// locationPath returns the schema path that is used to locate a change.
+func locationPath(c Change) string {
+	// paths of printer columns are JSONPaths, not schema paths
+	if strings.HasPrefix(c.Kind, "printerColumn.") {
+		return ""
+	}
+
+	return c.Path
+}
+
+func (e *encoder) encodeCRDDiff(d *compare.CRDDiff) {
+	e.encodeGeneralChanges(d.General)
+
+	for _, version := range d.AddedVersions {
+		e.add(Change{Kind: KindVersionAdded, Version: version, Severity: SeverityInfo, New: version})
+	}
+
+	for _, version := range d.DeletedVersions {
+		e.add(Change{Kind: KindVersionRemoved, Version: version, Severity: SeverityError, Old: version})
+	}
+
+	for _, version := range sets.List(sets.KeySet(d.ChangedVersions)) {
+		versionDiff := d.ChangedVersions[version]
+
+		e.version = version
+		e.encodeVersionDiff(&versionDiff)
+	}
+
+	e.version = ""
+}
+
+func (e *encoder) encodeGeneralChanges(changes []compare.Change) {
+	for _, c := range changes {
+		e.add(Change{Kind: KindGeneral, Severity: toSeverity(c.BreakingLevel()), Description: c.Description})
+	}
+}
+
+func (e *encoder) encodeVersionDiff(d *compare.CRDVersionDiff) {
+	e.encodeGeneralChanges(d.General)
+
+	for _, path := range sets.List(sets.KeySet(d.SchemaChanges)) {
+		schemaDiff := d.SchemaChanges[path]
+		e.encodeCRDSchemaDiff(path, &schemaDiff)
+	}
+
+	if columns := d.PrinterColumns; columns != nil {
+		for _, column := range columns.Added {
+			e.add(Change{Kind: KindPrinterColumnAdded, Path: column.JSONPath, Severity: SeverityInfo, New: column})
+		}
+
+		for _, column := range columns.Removed {
+			e.add(Change{Kind: KindPrinterColumnRemoved, Path: column.JSONPath, Severity: SeverityInfo, Old: column})
+		}
+
+		for _, change := range columns.Modified {
+			e.add(Change{Kind: KindPrinterColumnChanged, Path: change.Revision.JSONPath, Severity: SeverityInfo, Old: change.Base, New: change.Revision})
+		}
+
+		for _, column := range columns.Broken {
+			e.add(Change{Kind: KindPrinterColumnBroken, Path: column.JSONPath, Severity: SeverityWarning, New: column})
+		}
+	}
+
+	for _, bc := range d.BreakingChanges {
+		e.encodeBreakingChange(bc)
+	}
+}
+
+func (e *encoder) encodeCRDSchemaDiff(path string, d *compare.CRDSchemaDiff) {
+	for _, change := range report.CollectSchemaChanges(path, d) {
+		// these are never produced for CRDs and would only contain raw oasdiff data
+		if change.Attribute == "extensions" || change.Attribute == "discriminator" {
+			continue
+		}
+
+		e.add(Change{
+			Kind:      schemaChangeKind(change),
+			Path:      change.Path,
+			Subschema: change.Subschema,
+			Severity:  SeverityInfo,
+			Old:       change.Old,
+			New:       change.New,
+		})
+	}
+}
+
+// schemaChangeKind returns the kind of a schema change, e.g. "schema.maxLength"
+// for plain values or "schema.enum.value.added".
+func schemaChangeKind(c report.SchemaChange) string {
+	switch {
+	case c.Attribute == "":
+		return schemaAttributeKindPrefix + c.Action
+	case c.Action == "":
+		return schemaAttributeKindPrefix + c.Attribute
+	default:
+		return schemaAttributeKindPrefix + c.Attribute + "." + c.Action
+	}
+}
+
+func (e *encoder) encodeBreakingChange(bc compare.BreakingChange) {
+	// all details structs share the same field names
+	var common struct {
+		Path string      `json:"path"`
+		From interface{} `json:"from"`
+		To   interface{} `json:"to"`
+	}
+
+	if encoded, err := json.Marshal(bc.Details); err == nil {
+		// raw oasdiff messages have no path and cannot be decoded, which is fine
+		_ = json.Unmarshal(encoded, &common)
+	}
+
+	e.add(Change{
+		Kind:     KindBreaking,
+		Path:     common.Path,
+		Severity: toSeverity(bc.Level),
+		Old:      common.From,
+		New:      common.To,
+		ID:       bc.ID,
+		Details:  bc.Details,
+	})
+}
+
+func toSeverity(level checker.Level) Severity {
+	switch level {
+	case checker.ERR:
+		return SeverityError
+	case checker.WARN:
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/tufin/oasdiff/diff"
+	"github.com/tufin/oasdiff/utils"
+	"gopkg.in/yaml.v3"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+	"go.xrstf.de/crdiff/pkg/compare/report"
+	"go.xrstf.de/crdiff/pkg/crd"
+	"go.xrstf.de/crdiff/pkg/loader"
+)
+
+func TestRoundTrip(t *testing.T) {
+	testcases, err := filepath.Glob("../../testdata/*.base.yaml")
+	if err != nil {
+		t.Fatalf("Failed to find testcases: %v", err)
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	for _, baseFile := range testcases {
+		basename := strings.Replace(filepath.Base(baseFile), ".base.yaml", "", -1)
+		revisionFile := strings.Replace(baseFile, ".base.yaml", ".revision.yaml", -1)
+
+		for _, engine := range compare.Engines {
+			t.Run(fmt.Sprintf("%s/%s", basename, engine), func(t *testing.T) {
+				baseCRDs, err := loader.LoadCRDs(baseFile, nil, log)
+				if err != nil {
+					t.Fatalf("Failed to load base CRD: %v", err)
+				}
+
+				revisionCRDs, err := loader.LoadCRDs(revisionFile, nil, log)
+				if err != nil {
+					t.Fatalf("Failed to load revision CRD: %v", err)
+				}
+
+				original := &report.Report{
+					Diffs:   map[string]compare.CRDDiff{},
+					Sources: map[string]*crd.Source{},
+				}
+
+				for identifier, baseCRD := range baseCRDs {
+					result, err := compare.CompareCRDs(baseCRD, revisionCRDs[identifier], compare.CompareOptions{Engine: engine})
+					if err != nil {
+						t.Fatalf("Failed to compare CRDs: %v", err)
+					}
+
+					original.Diffs[identifier] = *result
+					original.Sources[identifier] = revisionCRDs[identifier].Source()
+				}
+
+				encoded, err := json.Marshal(FromReport(original))
+				if err != nil {
+					t.Fatalf("Failed to encode report: %v", err)
+				}
+
+				decoded, err := Decode(bytes.NewReader(encoded))
+				if err != nil {
+					t.Fatalf("Failed to decode report: %v", err)
+				}
+
+				reencoded, err := json.Marshal(FromReport(decoded))
+				if err != nil {
+					t.Fatalf("Failed to encode decoded report: %v", err)
+				}
+
+				if !bytes.Equal(encoded, reencoded) {
+					t.Fatalf("Report changed after decoding:\n\nOriginal: %s\n\nDecoded:  %s", encoded, reencoded)
+				}
+
+				// reports in the unversioned format must round-trip as well
+				legacyEncoded, err := json.Marshal(original)
+				if err != nil {
+					t.Fatalf("Failed to encode unversioned report: %v", err)
+				}
+
+				legacy := &report.Report{}
+				if err := json.Unmarshal(legacyEncoded, legacy); err != nil {
+					t.Fatalf("Failed to decode unversioned report: %v", err)
+				}
+
+				// the unversioned format does not contain any source locations
+				legacy.Sources = original.Sources
+
+				legacyReencoded, err := json.Marshal(FromReport(legacy))
+				if err != nil {
+					t.Fatalf("Failed to encode decoded unversioned report: %v", err)
+				}
+
+				if !bytes.Equal(encoded, legacyReencoded) {
+					t.Fatalf("Unversioned report changed after decoding:\n\nOriginal: %s\n\nDecoded:  %s", encoded, legacyReencoded)
+				}
+
+				// YAML must contain exactly the same data as JSON
+				var yamlEncoded bytes.Buffer
+				if err := EncodeYAML(&yamlEncoded, FromReport(original)); err != nil {
+					t.Fatalf("Failed to encode report as YAML: %v", err)
+				}
+
+				var yamlData, jsonData interface{}
+				if err := yaml.Unmarshal(yamlEncoded.Bytes(), &yamlData); err != nil {
+					t.Fatalf("Failed to decode YAML report: %v", err)
+				}
+
+				if err := json.Unmarshal(encoded, &jsonData); err != nil {
+					t.Fatalf("Failed to decode JSON report: %v", err)
+				}
+
+				// compare both by encoding them as JSON, which normalizes numbers
+				yamlNormalized, _ := json.Marshal(yamlData)
+				jsonNormalized, _ := json.Marshal(jsonData)
+
+				if !bytes.Equal(yamlNormalized, jsonNormalized) {
+					t.Fatalf("YAML report differs from JSON:\n\nJSON: %s\n\nYAML: %s", jsonNormalized, yamlNormalized)
+				}
+
+				if original.HighestLevel() != decoded.HighestLevel() {
+					t.Fatalf("Expected highest level %v, but got %v.", original.HighestLevel(), decoded.HighestLevel())
+				}
+			})
+		}
+	}
+}
+
+func TestDecodeRejectsInvalidValues(t *testing.T) {
+	testcases := []struct {
+		name   string
+		change string
+		valid  bool
+	}{
+		{name: "integer", change: `{"kind":"schema.maxLength","version":"v1","path":".spec.name","severity":"info","old":63,"new":15}`, valid: true},
+		{name: "integer set", change: `{"kind":"schema.maxLength","version":"v1","path":".spec.name","severity":"info","new":15}`, valid: true},
+		{name: "string integer", change: `{"kind":"schema.maxLength","version":"v1","path":".spec.name","severity":"info","old":"63","new":15}`},
+		{name: "fractional integer", change: `{"kind":"schema.maxLength","version":"v1","path":".spec.name","severity":"info","old":63,"new":1.5}`},
+		{name: "number", change: `{"kind":"schema.minimum","version":"v1","path":".spec.replicas","severity":"info","old":1,"new":1.5}`, valid: true},
+		{name: "boolean number", change: `{"kind":"schema.minimum","version":"v1","path":".spec.replicas","severity":"info","old":true}`},
+		{name: "string", change: `{"kind":"schema.type","version":"v1","path":".spec.replicas","severity":"info","old":"string","new":"integer"}`, valid: true},
+		{name: "object string", change: `{"kind":"schema.type","version":"v1","path":".spec.replicas","severity":"info","old":{},"new":"integer"}`},
+		{name: "boolean", change: `{"kind":"schema.nullable","version":"v1","path":".spec.replicas","severity":"info","old":false,"new":true}`, valid: true},
+		{name: "string boolean", change: `{"kind":"schema.nullable","version":"v1","path":".spec.replicas","severity":"info","old":"false","new":true}`},
+		{name: "any value", change: `{"kind":"schema.default","version":"v1","path":".spec.replicas","severity":"info","old":"1","new":[1]}`, valid: true},
+		{name: "property", change: `{"kind":"schema.property.added","version":"v1","path":".spec.foo","severity":"info","new":"foo"}`, valid: true},
+		{name: "property without name", change: `{"kind":"schema.property.added","version":"v1","path":".spec.foo","severity":"info","new":42}`},
+		{name: "typed change", change: `{"kind":"schema.preserveUnknownFields","version":"v1","path":".spec","severity":"info","old":"yes","new":true}`},
+		{name: "external docs", change: `{"kind":"schema.externalDocs.url","version":"v1","path":".spec","severity":"info","old":"https://a","new":"https://b"}`, valid: true},
+		{name: "object external docs", change: `{"kind":"schema.externalDocs.url","version":"v1","path":".spec","severity":"info","new":{"url":"https://b"}}`},
+		{name: "raw extensions", change: `{"kind":"schema.extensions","version":"v1","path":".spec","severity":"info","new":{"added":["x-foo"]}}`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			document := fmt.Sprintf(`{"apiVersion":%q,"crds":[{"name":"example.com/Thing","changes":[%s]}]}`, APIVersion, tc.change)
+
+			_, err := Decode(strings.NewReader(document))
+			if tc.valid && err != nil {
+				t.Fatalf("Expected report to be valid, but got error: %v", err)
+			}
+
+			if !tc.valid && err == nil {
+				t.Fatal("Expected report to be invalid, but it was accepted.")
+			}
+		})
+	}
+}
+
+func TestEncodeSchemaExtensions(t *testing.T) {
+	rep := &report.Report{
+		Diffs: map[string]compare.CRDDiff{
+			"example.com/Thing": {
+				ChangedVersions: map[string]compare.CRDVersionDiff{
+					"v1": {
+						SchemaChanges: map[string]compare.CRDSchemaDiff{
+							".spec": {
+								Diff: &diff.SchemaDiff{
+									ExtensionsDiff:    &diff.ExtensionsDiff{Added: utils.StringList{"x-foo"}},
+									DiscriminatorDiff: &diff.DiscriminatorDiff{Added: true},
+									ExternalDocsDiff: &diff.ExternalDocsDiff{
+										URLDiff: &diff.ValueDiff{From: "https://a", To: "https://b"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	encoded := FromReport(rep)
+	if len(encoded.CRDs) != 1 {
+		t.Fatalf("Expected one CRD, but got %+v", encoded.CRDs)
+	}
+
+	// extensions and discriminators are not part of the format
+	changes := encoded.CRDs[0].Changes
+	if len(changes) != 1 || changes[0].Kind != KindExternalDocsURL || changes[0].Old != "https://a" || changes[0].New != "https://b" {
+		t.Fatalf("Expected only the external docs URL change, but got %+v", changes)
+	}
+}
@@ -0,0 +1,389 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"go.xrstf.de/crdiff/pkg/compare"
+	"go.xrstf.de/crdiff/pkg/compare/report"
+	"go.xrstf.de/crdiff/pkg/crd"
+	"go.xrstf.de/crdiff/pkg/loader"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestEncodedReportsMatchSchema(t *testing.T) {
+	testcases, err := filepath.Glob("../../testdata/*.base.yaml")
+	if err != nil {
+		t.Fatalf("Failed to find testcases: %v", err)
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	for _, baseFile := range testcases {
+		basename := strings.Replace(filepath.Base(baseFile), ".base.yaml", "", -1)
+		revisionFile := strings.Replace(baseFile, ".base.yaml", ".revision.yaml", -1)
+
+		for _, engine := range compare.Engines {
+			t.Run(fmt.Sprintf("%s/%s", basename, engine), func(t *testing.T) {
+				baseCRDs, err := loader.LoadCRDs(baseFile, nil, log)
+				if err != nil {
+					t.Fatalf("Failed to load base CRD: %v", err)
+				}
+
+				revisionCRDs, err := loader.LoadCRDs(revisionFile, nil, log)
+				if err != nil {
+					t.Fatalf("Failed to load revision CRD: %v", err)
+				}
+
+				rep := &report.Report{
+					Diffs:   map[string]compare.CRDDiff{},
+					Sources: map[string]*crd.Source{},
+				}
+
+				for identifier, baseCRD := range baseCRDs {
+					result, err := compare.CompareCRDs(baseCRD, revisionCRDs[identifier], compare.CompareOptions{Engine: engine})
+					if err != nil {
+						t.Fatalf("Failed to compare CRDs: %v", err)
+					}
+
+					rep.Diffs[identifier] = *result
+					rep.Sources[identifier] = revisionCRDs[identifier].Source()
+				}
+
+				validateAgainstSchema(t, FromReport(rep))
+			})
+		}
+	}
+}
+
+func TestAddedAndRemovedCRDs(t *testing.T) {
+	rep := &report.Report{
+		Diffs: map[string]compare.CRDDiff{
+			"example.com/Removed": compare.RemovedCRDDiff(),
+		},
+		AddedCRDs: map[string]report.AddedCRD{
+			"example.com/Added": {
+				Scope:    "Namespaced",
+				Versions: []string{"v1"},
+				Names:    apiextensionsv1.CustomResourceDefinitionNames{Kind: "Added", Plural: "addeds"},
+			},
+		},
+		Sources: map[string]*crd.Source{
+			"example.com/Removed": {Filename: "base.yaml", Document: 2, Line: 1},
+		},
+	}
+
+	encoded := FromReport(rep)
+	validateAgainstSchema(t, encoded)
+
+	expected := map[string]string{
+		"example.com/Added":   KindCRDAdded,
+		"example.com/Removed": KindCRDRemoved,
+	}
+
+	for _, c := range encoded.CRDs {
+		if len(c.Changes) != 1 {
+			t.Fatalf("Expected exactly one change for %s, but got %+v.", c.Name, c.Changes)
+		}
+
+		if kind := c.Changes[0].Kind; kind != expected[c.Name] {
+			t.Errorf("Expected %s to be %q, but got %q.", c.Name, expected[c.Name], kind)
+		}
+	}
+
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		t.Fatalf("Failed to encode report: %v", err)
+	}
+
+	decoded, err := Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+
+	if d := decoded.Diffs["example.com/Removed"]; !d.Removed {
+		t.Errorf("Expected decoded CRD to be removed, but got %+v.", d)
+	}
+
+	if _, exists := decoded.AddedCRDs["example.com/Added"]; !exists {
+		t.Error("Expected decoded report to contain the added CRD.")
+	}
+
+	if decoded.HighestLevel() != rep.HighestLevel() {
+		t.Errorf("Expected highest level %v, but got %v.", rep.HighestLevel(), decoded.HighestLevel())
+	}
+}
+
+func validateAgainstSchema(t *testing.T, r *Report) {
+	t.Helper()
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(JSONSchema, &schema); err != nil {
+		t.Fatalf("Failed to decode JSON Schema: %v", err)
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("Failed to encode report: %v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+
+	v := &schemaValidator{root: schema}
+	for _, err := range v.validate(schema, document, "") {
+		t.Errorf("Report does not match JSON Schema: %v", err)
+	}
+}
+
+// schemaValidator implements the subset of JSON Schema that is used
+// by schema.json, which is enough to catch reports that drift away
+// from the documented format.
+type schemaValidator struct {
+	root map[string]interface{}
+}
+
+func (v *schemaValidator) validate(schema map[string]interface{}, value interface{}, path string) []error {
+	var errs []error
+
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", displayPointer(path), fmt.Sprintf(format, args...)))
+	}
+
+	for _, keyword := range sortedKeywords(schema) {
+		arg := schema[keyword]
+
+		switch keyword {
+		case "$schema", "$id", "$defs", "title", "description", "then":
+			// annotations; "$defs" is resolved by "$ref" and "then" is handled together with "if"
+
+		case "$ref":
+			ref := arg.(string)
+			name, found := strings.CutPrefix(ref, "#/$defs/")
+			if !found {
+				fail("unsupported $ref %q", ref)
+				continue
+			}
+
+			defs := v.root["$defs"].(map[string]interface{})
+			def, exists := defs[name].(map[string]interface{})
+			if !exists {
+				fail("unknown $ref %q", ref)
+				continue
+			}
+
+			errs = append(errs, v.validate(def, value, path)...)
+
+		case "type":
+			if !hasType(value, arg.(string)) {
+				fail("expected %s, got %T", arg, value)
+			}
+
+		case "const":
+			if !reflect.DeepEqual(value, arg) {
+				fail("expected %v, got %v", arg, value)
+			}
+
+		case "enum":
+			if !containsValue(arg.([]interface{}), value) {
+				fail("%v is not one of %v", value, arg)
+			}
+
+		case "minimum":
+			if n, ok := value.(json.Number); ok {
+				f, _ := n.Float64()
+				if f < arg.(float64) {
+					fail("%v is less than %v", f, arg)
+				}
+			}
+
+		case "required":
+			if obj, ok := value.(map[string]interface{}); ok {
+				for _, field := range arg.([]interface{}) {
+					if _, exists := obj[field.(string)]; !exists {
+						fail("required field %q is missing", field)
+					}
+				}
+			}
+
+		case "properties":
+			if obj, ok := value.(map[string]interface{}); ok {
+				properties := arg.(map[string]interface{})
+				for _, field := range sortedKeywords(obj) {
+					if property, exists := properties[field]; exists {
+						errs = append(errs, v.validate(property.(map[string]interface{}), obj[field], path+"/"+field)...)
+					}
+				}
+			}
+
+		case "additionalProperties":
+			if obj, ok := value.(map[string]interface{}); ok && arg == false {
+				properties, _ := schema["properties"].(map[string]interface{})
+				for _, field := range sortedKeywords(obj) {
+					if _, exists := properties[field]; !exists {
+						fail("unexpected field %q", field)
+					}
+				}
+			}
+
+		case "items":
+			if list, ok := value.([]interface{}); ok {
+				for i, item := range list {
+					errs = append(errs, v.validate(arg.(map[string]interface{}), item, fmt.Sprintf("%s/%d", path, i))...)
+				}
+			}
+
+		case "anyOf":
+			matched := false
+			for _, sub := range arg.([]interface{}) {
+				if len(v.validate(sub.(map[string]interface{}), value, path)) == 0 {
+					matched = true
+					break
+				}
+			}
+
+			if !matched {
+				fail("%v does not match any subschema", value)
+			}
+
+		case "allOf":
+			for _, sub := range arg.([]interface{}) {
+				errs = append(errs, v.validate(sub.(map[string]interface{}), value, path)...)
+			}
+
+		case "if":
+			if len(v.validate(arg.(map[string]interface{}), value, path)) == 0 {
+				if then, exists := schema["then"]; exists {
+					errs = append(errs, v.validate(then.(map[string]interface{}), value, path)...)
+				}
+			}
+
+		default:
+			fail("unsupported keyword %q", keyword)
+		}
+	}
+
+	return errs
+}
+
+func hasType(value interface{}, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	}
+
+	return false
+}
+
+func containsValue(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedKeywords(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func displayPointer(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	return path
+}
+
+func TestSchemaValidator(t *testing.T) {
+	valid := &Report{
+		APIVersion: APIVersion,
+		CRDs: []CRD{{
+			Name:    "example.com/Thing",
+			Changes: []Change{{Kind: KindGeneral, Severity: SeverityInfo, Description: "something changed"}},
+		}},
+	}
+
+	validateAgainstSchema(t, valid)
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(JSONSchema, &schema); err != nil {
+		t.Fatalf("Failed to decode JSON Schema: %v", err)
+	}
+
+	invalid := []struct {
+		name     string
+		document string
+	}{
+		{name: "wrong apiVersion", document: `{"apiVersion":"v2","crds":[]}`},
+		{name: "unknown kind", document: `{"apiVersion":"crdiff.xrstf.de/v1","crds":[{"name":"a","changes":[{"kind":"crd.renamed","severity":"info"}]}]}`},
+		{name: "unknown severity", document: `{"apiVersion":"crdiff.xrstf.de/v1","crds":[{"name":"a","changes":[{"kind":"version.added","severity":"fatal"}]}]}`},
+		{name: "breaking without id", document: `{"apiVersion":"crdiff.xrstf.de/v1","crds":[{"name":"a","changes":[{"kind":"breaking","severity":"error","details":{}}]}]}`},
+		{name: "unknown field", document: `{"apiVersion":"crdiff.xrstf.de/v1","crds":[{"name":"a","changes":[],"foo":1}]}`},
+		{name: "invalid line", document: `{"apiVersion":"crdiff.xrstf.de/v1","crds":[{"name":"a","changes":[{"kind":"crd.removed","severity":"error","location":{"file":"a.yaml","line":0}}]}]}`},
+	}
+
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			decoder := json.NewDecoder(strings.NewReader(tc.document))
+			decoder.UseNumber()
+
+			var document interface{}
+			if err := decoder.Decode(&document); err != nil {
+				t.Fatalf("Failed to decode document: %v", err)
+			}
+
+			v := &schemaValidator{root: schema}
+			if errs := v.validate(schema, document, ""); len(errs) == 0 {
+				t.Fatal("Expected document to be invalid, but it was accepted.")
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+// Package v1 defines the versioned JSON report format of crdiff. Unlike
+// report.Report, it does not expose any oasdiff types, but describes every
+// change as a flat record, so that its shape stays stable when crdiff's
+// internals change. The format is described by the JSON Schema in JSONSchema.
+//
+// Changes to schema extensions and discriminators are not part of the format:
+// CRD schemas cannot have a discriminator and neither schema diffing engine
+// compares extensions (the Kubernetes extensions are reported by their own
+// kinds instead).
+package v1
+
+import (
+	_ "embed"
+)
+
+// APIVersion identifies this report format.
+const APIVersion = "crdiff.xrstf.de/v1"
+
+// JSONSchema is the JSON Schema describing a Report.
+//
+//go:embed schema.json
+var JSONSchema []byte
+
+// Report is the result of comparing two sets of CRDs.
+type Report struct {
+	APIVersion string `json:"apiVersion"`
+	CRDs       []CRD  `json:"crds"`
+}
+
+// CRD contains all changes for a single CRD.
+type CRD struct {
+	// Name is the CRD identifier, e.g. "example.com/Thing".
+	Name    string   `json:"name"`
+	Changes []Change `json:"changes"`
+}
+
+// Change describes a single change.
+type Change struct {
+	// Kind is the type of change, one of the Kind* constants.
+	Kind string `json:"kind"`
+	// Version is the CRD version the change belongs to; empty for CRD-wide changes.
+	Version string `json:"version,omitempty"`
+	// Path is the path in the schema (e.g. ".spec.ports.[].name"), if applicable.
+	Path string `json:"path,omitempty"`
+	// Subschema locates changes inside nested schemas at Path, like
+	// "anyOf/#1/properties/foo"; segments are escaped like in JSON Pointers.
+	Subschema string `json:"subschema,omitempty"`
+	// Location is where the change can be found in the source file, if known.
+	Location *Location `json:"location,omitempty"`
+	Severity Severity  `json:"severity"`
+	// Old and New are the values before and after the change, if applicable.
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+	// Description is a human readable description for general changes.
+	Description string `json:"description,omitempty"`
+	// ID and Details are only set for breaking changes (KindBreaking).
+	ID      string      `json:"id,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Location is a position in the file a CRD was loaded from. For removed
+// CRDs this is the base, otherwise the revision.
+type Location struct {
+	File string `json:"file"`
+	// Document is the 1-based index of the YAML document within the file.
+	Document int `json:"document,omitempty"`
+	Line     int `json:"line,omitempty"`
+}
+
+// Severity describes how severe a change is. Changes with a severity of
+// warning or error are breaking changes.
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// These are the kinds of changes that can appear in a report.
+const (
+	KindCRDAdded       = "crd.added"
+	KindCRDRemoved     = "crd.removed"
+	KindGeneral        = "general"
+	KindVersionAdded   = "version.added"
+	KindVersionRemoved = "version.removed"
+	KindBreaking       = "breaking"
+
+	KindPrinterColumnAdded   = "printerColumn.added"
+	KindPrinterColumnRemoved = "printerColumn.removed"
+	KindPrinterColumnChanged = "printerColumn.changed"
+	KindPrinterColumnBroken  = "printerColumn.broken"
+
+	KindSchemaAdded             = "schema.added"
+	KindSchemaRemoved           = "schema.removed"
+	KindCircularRef             = "schema.circularRef"
+	KindExternalDocsAdded       = "schema.externalDocs.added"
+	KindExternalDocsRemoved     = "schema.externalDocs.removed"
+	KindExternalDocsDescription = "schema.externalDocs.description"
+	KindExternalDocsURL         = "schema.externalDocs.url"
+	KindPropertyAdded           = "schema.property.added"
+	KindPropertyRemoved         = "schema.property.removed"
+	KindRequiredAdded           = "schema.required.added"
+	KindRequiredRemoved         = "schema.required.removed"
+	KindEnumAdded               = "schema.enum.added"
+	KindEnumRemoved             = "schema.enum.removed"
+	KindEnumValueAdded          = "schema.enum.value.added"
+	KindEnumValueRemoved        = "schema.enum.value.removed"
+	KindAnyOfAdded              = "schema.anyOf.added"
+	KindAnyOfRemoved            = "schema.anyOf.removed"
+	KindOneOfAdded              = "schema.oneOf.added"
+	KindOneOfRemoved            = "schema.oneOf.removed"
+	KindAllOfAdded              = "schema.allOf.added"
+	KindAllOfRemoved            = "schema.allOf.removed"
+	KindListType                = "schema.listType"
+	KindListMapKeys             = "schema.listMapKeys"
+	KindValidationRuleAdded     = "schema.validationRule.added"
+	KindValidationRuleRemoved   = "schema.validationRule.removed"
+	KindValidationRuleChanged   = "schema.validationRule.changed"
+	KindPreserveUnknownFields   = "schema.preserveUnknownFields"
+	KindPrunedProperty          = "schema.prunedProperty"
+	KindEmbeddedResource        = "schema.embeddedResource"
+	KindMapType                 = "schema.mapType"
+)
+
+// Changes to plain schema attributes use the kind "schema.<attribute>",
+// e.g. "schema.type" or "schema.maxLength".
+const schemaAttributeKindPrefix = "schema."
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EncodeYAML writes the report as YAML. The report is encoded as JSON first
+// and then converted, so that both formats share exactly the same structure
+// and field order, and values decoded from JSON reports are kept intact.
+func EncodeYAML(w io.Writer, r *Report) error {
+	encoded, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(encoded, &node); err != nil {
+		return fmt.Errorf("failed to convert report: %w", err)
+	}
+
+	// do not keep the JSON flow style and quotes
+	resetStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&node); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	return encoder.Close()
+}
+
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
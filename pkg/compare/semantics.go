@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"github.com/tufin/oasdiff/checker"
+
+	"go.xrstf.de/crdiff/pkg/compare/oasdiff"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+const (
+	// intOrStringType is a synthetic type used to represent
+	// x-kubernetes-int-or-string fields when diffing with oasdiff.
+	intOrStringType = "int-or-string"
+
+	mapTypeGranular = "granular"
+	mapTypeAtomic   = "atomic"
+
+	propertyTypeChangedID = "request-property-type-changed"
+)
+
+// normalizeSchema returns a copy of the schema in which x-kubernetes-int-or-string
+// fields get the synthetic type "int-or-string" instead of their
+// anyOf[integer,string] construct, so that oasdiff can properly compare them.
+// Changes to x-kubernetes-embedded-resource are handled by compareTypeSemantics.
+func normalizeSchema(schema *apiextensionsv1.JSONSchemaProps) *apiextensionsv1.JSONSchemaProps {
+	if schema == nil {
+		return nil
+	}
+
+	normalized := schema.DeepCopy()
+	normalizeSchemaInPlace(normalized)
+
+	return normalized
+}
+
+func normalizeSchemaInPlace(schema *apiextensionsv1.JSONSchemaProps) {
+	if schema.XIntOrString {
+		schema.Type = intOrStringType
+		schema.AnyOf = withoutIntOrStringTypes(schema.AnyOf)
+
+		for i := range schema.AllOf {
+			schema.AllOf[i].AnyOf = withoutIntOrStringTypes(schema.AllOf[i].AnyOf)
+		}
+	}
+
+	for name, prop := range schema.Properties {
+		normalizeSchemaInPlace(&prop)
+		schema.Properties[name] = prop
+	}
+
+	if schema.Items != nil && schema.Items.Schema != nil {
+		normalizeSchemaInPlace(schema.Items.Schema)
+	}
+
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		normalizeSchemaInPlace(schema.AdditionalProperties.Schema)
+	}
+}
+
+// withoutIntOrStringTypes removes the {type: integer} and {type: string}
+// entries that make up an int-or-string anyOf.
+func withoutIntOrStringTypes(schemas []apiextensionsv1.JSONSchemaProps) []apiextensionsv1.JSONSchemaProps {
+	var result []apiextensionsv1.JSONSchemaProps
+
+	for _, s := range schemas {
+		isBare := equality.Semantic.DeepEqual(s, apiextensionsv1.JSONSchemaProps{Type: s.Type})
+		if isBare && (s.Type == "integer" || s.Type == "string") {
+			continue
+		}
+
+		result = append(result, s)
+	}
+
+	return result
+}
+
+// dropCompatibleTypeChanges removes the breaking changes reported by oasdiff
+// when an integer or string field is turned into an int-or-string field, as
+// all previously valid values remain valid.
+func dropCompatibleTypeChanges(result *CRDVersionDiff) {
+	filtered := []BreakingChange{}
+
+	for _, change := range result.BreakingChanges {
+		if msg, ok := change.Details.(*oasdiff.PropertyTypeChangedMessage); ok && change.ID == propertyTypeChangedID {
+			if msg.To == intOrStringType && (msg.From == "integer" || msg.From == "string") {
+				continue
+			}
+		}
+
+		filtered = append(filtered, change)
+	}
+
+	result.BreakingChanges = filtered
+}
+
+// compareTypeSemantics detects changes to x-kubernetes-embedded-resource and
+// x-kubernetes-map-type, which oasdiff does not understand.
+func compareTypeSemantics(result *CRDVersionDiff, base, revision *apiextensionsv1.JSONSchemaProps) {
+	walkSchemas(base, revision, "", func(path string, b, r *apiextensionsv1.JSONSchemaProps) {
+		if b.XEmbeddedResource != r.XEmbeddedResource {
+			updateSchemaDiff(result, path, func(d *CRDSchemaDiff) {
+				d.EmbeddedResource = &BoolChange{From: b.XEmbeddedResource, To: r.XEmbeddedResource}
+			})
+
+			switch {
+			// embedded resources require apiVersion and kind, which existing objects might lack
+			case r.XEmbeddedResource:
+				result.BreakingChanges = append(result.BreakingChanges, BreakingChange{
+					ID:    EmbeddedResourceEnabledID,
+					Level: checker.ERR,
+					Details: &EmbeddedResourceEnabledMessage{
+						Path: path,
+					},
+				})
+
+			// apiVersion, kind and metadata were implicitly preserved and are now
+			// pruned, unless they are declared or unknown fields are preserved
+			case !preservesUnknownFields(r):
+				if pruned := undeclaredEmbeddedResourceFields(r); len(pruned) > 0 {
+					result.BreakingChanges = append(result.BreakingChanges, BreakingChange{
+						ID:    EmbeddedResourceDisabledID,
+						Level: checker.ERR,
+						Details: &EmbeddedResourceDisabledMessage{
+							Path:   path,
+							Fields: pruned,
+						},
+					})
+				}
+			}
+		}
+
+		// type changes are already reported by oasdiff
+		if b.Type != "object" || r.Type != "object" {
+			return
+		}
+
+		baseType := mapType(b)
+		revisionType := mapType(r)
+
+		if baseType != revisionType {
+			updateSchemaDiff(result, path, func(d *CRDSchemaDiff) {
+				d.MapType = &StringChange{From: baseType, To: revisionType}
+			})
+
+			// atomic maps can only be owned by a single field manager
+			if revisionType == mapTypeAtomic {
+				result.BreakingChanges = append(result.BreakingChanges, BreakingChange{
+					ID:    MapTypeChangedID,
+					Level: checker.WARN,
+					Details: &MapTypeChangedMessage{
+						Path: path,
+						From: baseType,
+						To:   revisionType,
+					},
+				})
+			}
+		}
+	})
+}
+
+// mapType returns the effective map type; maps are granular by default.
+func mapType(schema *apiextensionsv1.JSONSchemaProps) string {
+	if schema.XMapType == nil || *schema.XMapType == "" {
+		return mapTypeGranular
+	}
+
+	return *schema.XMapType
+}
+
+// undeclaredEmbeddedResourceFields returns the implicit fields of an embedded
+// resource that are not declared as properties in the given schema.
+func undeclaredEmbeddedResourceFields(schema *apiextensionsv1.JSONSchemaProps) []string {
+	result := []string{}
+
+	for _, name := range []string{"apiVersion", "kind", "metadata"} {
+		if _, exists := schema.Properties[name]; !exists {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"fmt"
+
+	"github.com/tufin/oasdiff/checker"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// compareSubresources compares the status and scale subresources of a single
+// version. Additionally the scale paths of the revision are checked against
+// the revision's schema; paths that were already broken in the base are not
+// reported again.
+func compareSubresources(base, revision *apiextensionsv1.CustomResourceSubresources, baseSchema, revisionSchema *apiextensionsv1.JSONSchemaProps) []Change {
+	if base == nil {
+		base = &apiextensionsv1.CustomResourceSubresources{}
+	}
+
+	if revision == nil {
+		revision = &apiextensionsv1.CustomResourceSubresources{}
+	}
+
+	changes := []Change{}
+
+	switch {
+	case base.Status != nil && revision.Status == nil:
+		changes = append(changes, Change{
+			Breaking:    true,
+			Level:       checker.ERR,
+			Description: "disabled status subresource; status is now updated together with the main resource",
+		})
+
+	case base.Status == nil && revision.Status != nil:
+		changes = append(changes, Change{
+			Description: "enabled status subresource; status changes on the main resource are now ignored",
+		})
+	}
+
+	switch {
+	case base.Scale != nil && revision.Scale == nil:
+		changes = append(changes, Change{
+			Breaking:    true,
+			Level:       checker.ERR,
+			Description: "disabled scale subresource",
+		})
+
+	case base.Scale == nil && revision.Scale != nil:
+		changes = append(changes, Change{
+			Description: "enabled scale subresource",
+		})
+
+	case base.Scale != nil && revision.Scale != nil:
+		changes = append(changes, compareScalePath("specReplicasPath", base.Scale.SpecReplicasPath, revision.Scale.SpecReplicasPath)...)
+		changes = append(changes, compareScalePath("statusReplicasPath", base.Scale.StatusReplicasPath, revision.Scale.StatusReplicasPath)...)
+		changes = append(changes, compareScalePath("labelSelectorPath", stringValue(base.Scale.LabelSelectorPath), stringValue(revision.Scale.LabelSelectorPath))...)
+	}
+
+	if scale := revision.Scale; scale != nil {
+		baseScale := base.Scale
+		if baseScale == nil {
+			baseScale = &apiextensionsv1.CustomResourceSubresourceScale{}
+		}
+
+		for _, p := range []struct {
+			name     string
+			basePath string
+			path     string
+		}{
+			{"specReplicasPath", baseScale.SpecReplicasPath, scale.SpecReplicasPath},
+			{"statusReplicasPath", baseScale.StatusReplicasPath, scale.StatusReplicasPath},
+			{"labelSelectorPath", stringValue(baseScale.LabelSelectorPath), stringValue(scale.LabelSelectorPath)},
+		} {
+			if p.path == "" || schemaHasPath(revisionSchema, p.path) {
+				continue
+			}
+
+			// only report paths that are newly broken
+			if p.basePath != p.path || schemaHasPath(baseSchema, p.path) {
+				changes = append(changes, Change{
+					Breaking:    true,
+					Level:       checker.ERR,
+					Description: fmt.Sprintf("scale subresource %s %s does not exist in the schema", p.name, p.path),
+				})
+			}
+		}
+	}
+
+	return changes
+}
+
+func compareScalePath(name string, base, revision string) []Change {
+	if base == revision {
+		return nil
+	}
+
+	// labelSelectorPath is optional and adding it is harmless
+	if base == "" {
+		return []Change{{
+			Description: fmt.Sprintf("set scale subresource %s to %s", name, revision),
+		}}
+	}
+
+	if revision == "" {
+		return []Change{{
+			Breaking:    true,
+			Level:       checker.ERR,
+			Description: fmt.Sprintf("removed scale subresource %s %s", name, base),
+		}}
+	}
+
+	return []Change{{
+		Breaking:    true,
+		Level:       checker.ERR,
+		Description: fmt.Sprintf("changed scale subresource %s from %s to %s", name, base, revision),
+	}}
+}
@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestCompareSubresourcesAlreadyBroken(t *testing.T) {
+	schema := &apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"replicas": {Type: "integer"},
+				},
+			},
+		},
+	}
+
+	subresources := &apiextensionsv1.CustomResourceSubresources{
+		Scale: &apiextensionsv1.CustomResourceSubresourceScale{
+			SpecReplicasPath:   ".spec.replicas",
+			StatusReplicasPath: ".status.replicas",
+		},
+	}
+
+	if changes := compareSubresources(subresources, subresources, schema, schema); len(changes) > 0 {
+		t.Errorf("Expected no changes when comparing a CRD with itself, but got %+v", changes)
+	}
+
+	// a path that only breaks with the revision schema must be reported
+	baseSchema := schema.DeepCopy()
+	baseSchema.Properties["status"] = apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]apiextensionsv1.JSONSchemaProps{
+			"replicas": {Type: "integer"},
+		},
+	}
+
+	changes := compareSubresources(subresources, subresources, baseSchema, schema)
+	if len(changes) != 1 || !changes[0].Breaking {
+		t.Fatalf("Expected exactly one breaking change, but got %+v", changes)
+	}
+
+	// as must paths that were changed to a non-existing field
+	revision := subresources.DeepCopy()
+	revision.Scale.SpecReplicasPath = ".spec.size"
+
+	changes = compareSubresources(subresources, revision, schema, schema)
+	if len(changes) != 2 {
+		t.Fatalf("Expected a changed and a broken path, but got %+v", changes)
+	}
+}
@@ -4,6 +4,9 @@
 package compare
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/tufin/oasdiff/checker"
 	"github.com/tufin/oasdiff/diff"
 	"github.com/tufin/oasdiff/utils"
@@ -12,20 +15,62 @@ import (
 )
 
 // Change is a generic change that is not schema-specific, e.g. when
-// a CRD scope was changed.
+// a CRD scope was changed. The level is only relevant for breaking
+// changes.
 type Change struct {
-	Breaking    bool   `json:"breaking" yaml:"breaking"`
-	Description string `json:"description,omitempty" yaml:"description"`
+	Breaking    bool          `json:"breaking" yaml:"breaking"`
+	Level       checker.Level `json:"level,omitempty" yaml:"level,omitempty"`
+	Description string        `json:"description,omitempty" yaml:"description"`
+}
+
+// BreakingLevel returns the level of a breaking change, or 0 if the change
+// is not breaking. Breaking changes without a level are treated as errors.
+func (c Change) BreakingLevel() checker.Level {
+	switch {
+	case !c.Breaking:
+		return 0
+	case c.Level == 0:
+		return checker.ERR
+	default:
+		return c.Level
+	}
+}
+
+func highestChangeLevel(changes []Change) checker.Level {
+	var level checker.Level
+
+	for _, c := range changes {
+		if l := c.BreakingLevel(); l > level {
+			level = l
+		}
+	}
+
+	return level
 }
 
 // CRDDiff describes all differences for all versions of a single CRD.
 type CRDDiff struct {
+	// Removed is true if the entire CRD has been removed; use RemovedCRDDiff
+	// to construct such a diff.
+	Removed         bool                      `json:"removed,omitempty" yaml:"removed,omitempty"`
 	General         []Change                  `json:"generalChanges,omitempty" yaml:"generalChanges,omitempty"`
 	AddedVersions   utils.StringList          `json:"added,omitempty" yaml:"added,omitempty"`
 	DeletedVersions utils.StringList          `json:"deleted,omitempty" yaml:"deleted,omitempty"`
 	ChangedVersions map[string]CRDVersionDiff `json:"changed,omitempty" yaml:"changed,omitempty"`
 }
 
+// RemovedCRDDiff returns the diff for a CRD that does not exist anymore.
+func RemovedCRDDiff() CRDDiff {
+	return CRDDiff{
+		Removed: true,
+		General: []Change{{
+			Breaking:    true,
+			Level:       checker.ERR,
+			Description: "CRD has been removed",
+		}},
+	}
+}
+
 func (d *CRDDiff) HasChanges() bool {
 	if d == nil {
 		return false
@@ -76,12 +121,66 @@ func (d *CRDDiff) HasBreakingChanges() bool {
 	return false
 }
 
+// HighestLevel returns the most severe level of all breaking changes
+// in this diff, or 0 if there are no breaking changes. Deleted versions
+// are always considered errors.
+func (d *CRDDiff) HighestLevel() checker.Level {
+	if d == nil {
+		return 0
+	}
+
+	if d.DeletedVersions.Len() > 0 {
+		return checker.ERR
+	}
+
+	level := highestChangeLevel(d.General)
+
+	for _, versionDiff := range d.ChangedVersions {
+		if l := versionDiff.HighestLevel(); l > level {
+			level = l
+		}
+	}
+
+	return level
+}
+
+// OnlyBreaking returns a copy of the diff without any changes that are
+// not breaking, i.e. what CompareCRDs returns with BreakingOnly enabled,
+// but without any schema changes.
+func (d *CRDDiff) OnlyBreaking() CRDDiff {
+	result := CRDDiff{
+		Removed:         d.Removed,
+		General:         onlyBreaking(d.General),
+		DeletedVersions: d.DeletedVersions,
+		ChangedVersions: map[string]CRDVersionDiff{},
+	}
+
+	for version, versionDiff := range d.ChangedVersions {
+		filtered := CRDVersionDiff{
+			General:         onlyBreaking(versionDiff.General),
+			SchemaChanges:   map[string]CRDSchemaDiff{},
+			BreakingChanges: versionDiff.BreakingChanges,
+		}
+
+		if columns := versionDiff.PrinterColumns; columns.HasBreakingChanges() {
+			filtered.PrinterColumns = &PrinterColumnsDiff{Broken: columns.Broken}
+		}
+
+		if filtered.HasBreakingChanges() {
+			result.ChangedVersions[version] = filtered
+		}
+	}
+
+	return result
+}
+
 func (in *CRDDiff) DeepCopy() *CRDDiff {
 	if in == nil {
 		return nil
 	}
 
 	out := &CRDDiff{
+		Removed:         in.Removed,
 		General:         make([]Change, len(in.General)),
 		AddedVersions:   make(utils.StringList, len(in.AddedVersions)),
 		DeletedVersions: make(utils.StringList, len(in.DeletedVersions)),
@@ -103,7 +202,9 @@ func (in *CRDDiff) DeepCopy() *CRDDiff {
 // CRD version (e.g. all changes for core/v1's Pods).
 // +k8s:deepcopy-gen=true
 type CRDVersionDiff struct {
+	General         []Change                 `json:"generalChanges,omitempty" yaml:"generalChanges,omitempty"`
 	SchemaChanges   map[string]CRDSchemaDiff `json:"schemaChanges,omitempty" yaml:"schemaChanges,omitempty"`
+	PrinterColumns  *PrinterColumnsDiff      `json:"printerColumns,omitempty" yaml:"printerColumns,omitempty"`
 	BreakingChanges []BreakingChange         `json:"breakingChanges,omitempty" yaml:"breakingChanges,omitempty"`
 }
 
@@ -112,7 +213,7 @@ func (d *CRDVersionDiff) HasChanges() bool {
 		return false
 	}
 
-	return len(d.SchemaChanges) > 0
+	return len(d.General) > 0 || len(d.SchemaChanges) > 0 || !d.PrinterColumns.Empty()
 }
 
 func (d *CRDVersionDiff) HasBreakingChanges() bool {
@@ -120,35 +221,153 @@ func (d *CRDVersionDiff) HasBreakingChanges() bool {
 		return false
 	}
 
+	for _, c := range d.General {
+		if c.Breaking {
+			return true
+		}
+	}
+
+	if d.PrinterColumns.HasBreakingChanges() {
+		return true
+	}
+
 	return len(d.BreakingChanges) > 0
 }
 
+// HighestLevel returns the most severe level of all breaking changes
+// in this version, or 0 if there are no breaking changes.
+func (d *CRDVersionDiff) HighestLevel() checker.Level {
+	if d == nil {
+		return 0
+	}
+
+	level := highestChangeLevel(d.General)
+
+	if d.PrinterColumns.HasBreakingChanges() && level < checker.WARN {
+		level = checker.WARN
+	}
+
+	for _, bc := range d.BreakingChanges {
+		if bc.Level > level {
+			level = bc.Level
+		}
+	}
+
+	return level
+}
+
 func (in *CRDVersionDiff) DeepCopy() *CRDVersionDiff {
 	if in == nil {
 		return nil
 	}
 
 	out := &CRDVersionDiff{
+		General:         make([]Change, len(in.General)),
 		SchemaChanges:   make(map[string]CRDSchemaDiff, len(in.SchemaChanges)),
 		BreakingChanges: make([]BreakingChange, len(in.BreakingChanges)),
 	}
 
+	copy(out.General, in.General)
 	copy(out.BreakingChanges, in.BreakingChanges)
 
 	for k, v := range in.SchemaChanges {
 		out.SchemaChanges[k] = *v.DeepCopy()
 	}
 
+	out.PrinterColumns = in.PrinterColumns.DeepCopy()
+
+	return out
+}
+
+// PrinterColumnsDiff describes the changes to the additionalPrinterColumns
+// of a single CRD version. Broken columns are columns in the revision whose
+// JSONPath does not point to an existing field in the revision's schema
+// anymore; columns that were already broken in the base are not included.
+type PrinterColumnsDiff struct {
+	Added    []PrinterColumn       `json:"added,omitempty" yaml:"added,omitempty"`
+	Removed  []PrinterColumn       `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Modified []PrinterColumnChange `json:"modified,omitempty" yaml:"modified,omitempty"`
+	Broken   []PrinterColumn       `json:"broken,omitempty" yaml:"broken,omitempty"`
+}
+
+func (d *PrinterColumnsDiff) Empty() bool {
+	if d == nil {
+		return true
+	}
+
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0 && len(d.Broken) == 0
+}
+
+// HasBreakingChanges returns true if there are broken columns; columns are
+// only informational, so changing them is not considered breaking.
+func (d *PrinterColumnsDiff) HasBreakingChanges() bool {
+	if d == nil {
+		return false
+	}
+
+	return len(d.Broken) > 0
+}
+
+func (in *PrinterColumnsDiff) DeepCopy() *PrinterColumnsDiff {
+	if in == nil {
+		return nil
+	}
+
+	out := &PrinterColumnsDiff{
+		Added:    make([]PrinterColumn, len(in.Added)),
+		Removed:  make([]PrinterColumn, len(in.Removed)),
+		Modified: make([]PrinterColumnChange, len(in.Modified)),
+		Broken:   make([]PrinterColumn, len(in.Broken)),
+	}
+
+	copy(out.Added, in.Added)
+	copy(out.Removed, in.Removed)
+	copy(out.Modified, in.Modified)
+	copy(out.Broken, in.Broken)
+
 	return out
 }
 
+// PrinterColumn is a single additionalPrinterColumn of a CRD version.
+type PrinterColumn struct {
+	Name        string `json:"name" yaml:"name"`
+	Type        string `json:"type" yaml:"type"`
+	Format      string `json:"format,omitempty" yaml:"format,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Priority    int32  `json:"priority,omitempty" yaml:"priority,omitempty"`
+	JSONPath    string `json:"jsonPath" yaml:"jsonPath"`
+}
+
+// PrinterColumnChange contains the base and revision of a column
+// that exists in both, but has been modified.
+type PrinterColumnChange struct {
+	Name     string        `json:"name" yaml:"name"`
+	Base     PrinterColumn `json:"base" yaml:"base"`
+	Revision PrinterColumn `json:"revision" yaml:"revision"`
+}
+
 // CRDSchemaDiff contains the changes at a given path within
 // a single CRD's version schema (e.g. changes at spec.clusterRef in
-// core/v1's Pod).
+// core/v1's Pod). Besides the generic OpenAPI diff, it contains typed
+// changes for Kubernetes-specific extensions.
 type CRDSchemaDiff struct {
-	AddedProperties   utils.StringList `json:"added,omitempty" yaml:"added,omitempty"`
-	DeletedProperties utils.StringList `json:"deleted,omitempty" yaml:"deleted,omitempty"`
-	Diff              *diff.SchemaDiff `json:"changes,omitempty" yaml:"changes,omitempty"`
+	AddedProperties   utils.StringList     `json:"added,omitempty" yaml:"added,omitempty"`
+	DeletedProperties utils.StringList     `json:"deleted,omitempty" yaml:"deleted,omitempty"`
+	Diff              *diff.SchemaDiff     `json:"changes,omitempty" yaml:"changes,omitempty"`
+	ListType          *StringChange        `json:"listType,omitempty" yaml:"listType,omitempty"`
+	ListMapKeys       *StringListChange    `json:"listMapKeys,omitempty" yaml:"listMapKeys,omitempty"`
+	ValidationRules   *ValidationRulesDiff `json:"validationRules,omitempty" yaml:"validationRules,omitempty"`
+
+	// PreserveUnknownFields is set when x-kubernetes-preserve-unknown-fields
+	// has been toggled at this path.
+	PreserveUnknownFields *BoolChange `json:"preserveUnknownFields,omitempty" yaml:"preserveUnknownFields,omitempty"`
+
+	// PrunedProperties are newly declared properties at a path that preserves
+	// unknown fields; any other data stored in these properties will be pruned.
+	PrunedProperties utils.StringList `json:"prunedProperties,omitempty" yaml:"prunedProperties,omitempty"`
+
+	EmbeddedResource *BoolChange   `json:"embeddedResource,omitempty" yaml:"embeddedResource,omitempty"`
+	MapType          *StringChange `json:"mapType,omitempty" yaml:"mapType,omitempty"`
 }
 
 func (in *CRDSchemaDiff) DeepCopy() *CRDSchemaDiff {
@@ -157,13 +376,126 @@ func (in *CRDSchemaDiff) DeepCopy() *CRDSchemaDiff {
 	}
 
 	out := &CRDSchemaDiff{
-		AddedProperties:   make(utils.StringList, len(in.AddedProperties)),
-		DeletedProperties: make(utils.StringList, len(in.DeletedProperties)),
-		Diff:              oasdiff.DeepCopySchemaDiff(in.Diff),
+		AddedProperties:       make(utils.StringList, len(in.AddedProperties)),
+		DeletedProperties:     make(utils.StringList, len(in.DeletedProperties)),
+		Diff:                  oasdiff.DeepCopySchemaDiff(in.Diff),
+		ListType:              in.ListType.DeepCopy(),
+		ListMapKeys:           in.ListMapKeys.DeepCopy(),
+		ValidationRules:       in.ValidationRules.DeepCopy(),
+		PreserveUnknownFields: in.PreserveUnknownFields.DeepCopy(),
+		PrunedProperties:      make(utils.StringList, len(in.PrunedProperties)),
+		EmbeddedResource:      in.EmbeddedResource.DeepCopy(),
+		MapType:               in.MapType.DeepCopy(),
 	}
 
 	copy(out.AddedProperties, in.AddedProperties)
 	copy(out.DeletedProperties, in.DeletedProperties)
+	copy(out.PrunedProperties, in.PrunedProperties)
+
+	return out
+}
+
+// ValidationRulesDiff describes the changes to the CEL rules
+// in x-kubernetes-validations at a single path.
+type ValidationRulesDiff struct {
+	Added    []ValidationRule       `json:"added,omitempty" yaml:"added,omitempty"`
+	Removed  []ValidationRule       `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Modified []ValidationRuleChange `json:"modified,omitempty" yaml:"modified,omitempty"`
+}
+
+func (d *ValidationRulesDiff) Empty() bool {
+	if d == nil {
+		return true
+	}
+
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+func (in *ValidationRulesDiff) DeepCopy() *ValidationRulesDiff {
+	if in == nil {
+		return nil
+	}
+
+	out := &ValidationRulesDiff{
+		Added:    make([]ValidationRule, len(in.Added)),
+		Removed:  make([]ValidationRule, len(in.Removed)),
+		Modified: make([]ValidationRuleChange, len(in.Modified)),
+	}
+
+	copy(out.Added, in.Added)
+	copy(out.Removed, in.Removed)
+	copy(out.Modified, in.Modified)
+
+	return out
+}
+
+// ValidationRule is a single CEL rule from x-kubernetes-validations.
+type ValidationRule struct {
+	Rule              string `json:"rule" yaml:"rule"`
+	Message           string `json:"message,omitempty" yaml:"message,omitempty"`
+	MessageExpression string `json:"messageExpression,omitempty" yaml:"messageExpression,omitempty"`
+	Reason            string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	FieldPath         string `json:"fieldPath,omitempty" yaml:"fieldPath,omitempty"`
+	OptionalOldSelf   bool   `json:"optionalOldSelf,omitempty" yaml:"optionalOldSelf,omitempty"`
+}
+
+// ValidationRuleChange contains the base and revision of a modified rule.
+type ValidationRuleChange struct {
+	From ValidationRule `json:"from" yaml:"from"`
+	To   ValidationRule `json:"to" yaml:"to"`
+}
+
+// StringChange describes a changed string value.
+type StringChange struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+func (in *StringChange) DeepCopy() *StringChange {
+	if in == nil {
+		return nil
+	}
+
+	out := *in
+
+	return &out
+}
+
+// BoolChange describes a changed boolean value.
+type BoolChange struct {
+	From bool `json:"from" yaml:"from"`
+	To   bool `json:"to" yaml:"to"`
+}
+
+func (in *BoolChange) DeepCopy() *BoolChange {
+	if in == nil {
+		return nil
+	}
+
+	out := *in
+
+	return &out
+}
+
+// StringListChange describes a changed list of strings, where the
+// order of items is relevant.
+type StringListChange struct {
+	From []string `json:"from" yaml:"from"`
+	To   []string `json:"to" yaml:"to"`
+}
+
+func (in *StringListChange) DeepCopy() *StringListChange {
+	if in == nil {
+		return nil
+	}
+
+	out := &StringListChange{
+		From: make([]string, len(in.From)),
+		To:   make([]string, len(in.To)),
+	}
+
+	copy(out.From, in.From)
+	copy(out.To, in.To)
 
 	return out
 }
@@ -176,3 +508,30 @@ type BreakingChange struct {
 	Level   checker.Level `json:"level" yaml:"level"`
 	Details interface{}   `json:"details" yaml:"details"`
 }
+
+// UnmarshalJSON decodes the details into the concrete message type
+// for the change's ID, so that decoded reports can be rendered again.
+func (b *BreakingChange) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID      string          `json:"id"`
+		Level   checker.Level   `json:"level"`
+		Details json.RawMessage `json:"details"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	details := NewBreakingChangeDetails(raw.ID)
+	if len(raw.Details) > 0 {
+		if err := json.Unmarshal(raw.Details, details); err != nil {
+			return fmt.Errorf("invalid details for %s: %w", raw.ID, err)
+		}
+	}
+
+	b.ID = raw.ID
+	b.Level = raw.Level
+	b.Details = details
+
+	return nil
+}
@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"github.com/tufin/oasdiff/checker"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// compareValidationRules detects changes to the CEL rules in
+// x-kubernetes-validations. New rules or changed expressions can reject
+// objects that were previously valid, so they are considered potentially
+// breaking.
+func compareValidationRules(result *CRDVersionDiff, base, revision *apiextensionsv1.JSONSchemaProps) {
+	walkSchemas(base, revision, "", func(path string, b, r *apiextensionsv1.JSONSchemaProps) {
+		rulesDiff := diffValidationRules(b.XValidations, r.XValidations)
+		if rulesDiff.Empty() {
+			return
+		}
+
+		updateSchemaDiff(result, path, func(d *CRDSchemaDiff) {
+			d.ValidationRules = rulesDiff
+		})
+
+		for _, rule := range rulesDiff.Added {
+			result.BreakingChanges = append(result.BreakingChanges, BreakingChange{
+				ID:    ValidationRuleAddedID,
+				Level: checker.WARN,
+				Details: &ValidationRuleAddedMessage{
+					Path: path,
+					Rule: rule.Rule,
+				},
+			})
+		}
+
+		for _, change := range rulesDiff.Modified {
+			level := validationRuleChangeLevel(change)
+			if level == 0 {
+				continue
+			}
+
+			result.BreakingChanges = append(result.BreakingChanges, BreakingChange{
+				ID:    ValidationRuleChangedID,
+				Level: level,
+				Details: &ValidationRuleChangedMessage{
+					Path: path,
+					From: change.From.Rule,
+					To:   change.To.Rule,
+				},
+			})
+		}
+	})
+}
+
+// validationRuleChangeLevel classifies a modified rule. Changing the
+// expression or making oldSelf optional (which makes transition rules also
+// apply when there is no old object) can reject previously valid objects.
+// Changing the fieldPath does not affect validity, but changes the errors
+// that clients see. Only changing the message or reason is harmless, so 0
+// is returned in that case.
+func validationRuleChangeLevel(change ValidationRuleChange) checker.Level {
+	from, to := change.From, change.To
+
+	switch {
+	case from.Rule != to.Rule:
+		return checker.WARN
+	case !from.OptionalOldSelf && to.OptionalOldSelf:
+		return checker.WARN
+	case from.FieldPath != to.FieldPath:
+		return checker.INFO
+	default:
+		return 0
+	}
+}
+
+// diffValidationRules matches rules first by their expression. All remaining
+// rules are matched by their position, as rules are usually edited in place.
+func diffValidationRules(base, revision apiextensionsv1.ValidationRules) *ValidationRulesDiff {
+	result := &ValidationRulesDiff{}
+
+	baseRules := make([]*ValidationRule, len(base))
+	for i, rule := range base {
+		baseRules[i] = newValidationRule(rule)
+	}
+
+	revisionRules := make([]*ValidationRule, len(revision))
+	for i, rule := range revision {
+		revisionRules[i] = newValidationRule(rule)
+	}
+
+	// pair rules with identical expressions
+	for i, baseRule := range baseRules {
+		for j, revisionRule := range revisionRules {
+			if revisionRule == nil || baseRule.Rule != revisionRule.Rule {
+				continue
+			}
+
+			if *baseRule != *revisionRule {
+				result.Modified = append(result.Modified, ValidationRuleChange{From: *baseRule, To: *revisionRule})
+			}
+
+			baseRules[i] = nil
+			revisionRules[j] = nil
+			break
+		}
+	}
+
+	baseRules = compactRules(baseRules)
+	revisionRules = compactRules(revisionRules)
+
+	// pair remaining rules by their position
+	for len(baseRules) > 0 && len(revisionRules) > 0 {
+		result.Modified = append(result.Modified, ValidationRuleChange{From: *baseRules[0], To: *revisionRules[0]})
+
+		baseRules = baseRules[1:]
+		revisionRules = revisionRules[1:]
+	}
+
+	for _, rule := range baseRules {
+		result.Removed = append(result.Removed, *rule)
+	}
+
+	for _, rule := range revisionRules {
+		result.Added = append(result.Added, *rule)
+	}
+
+	return result
+}
+
+func compactRules(rules []*ValidationRule) []*ValidationRule {
+	result := []*ValidationRule{}
+
+	for _, rule := range rules {
+		if rule != nil {
+			result = append(result, rule)
+		}
+	}
+
+	return result
+}
+
+func newValidationRule(rule apiextensionsv1.ValidationRule) *ValidationRule {
+	result := &ValidationRule{
+		Rule:              rule.Rule,
+		Message:           rule.Message,
+		MessageExpression: rule.MessageExpression,
+		FieldPath:         rule.FieldPath,
+	}
+
+	if rule.Reason != nil {
+		result.Reason = string(*rule.Reason)
+	}
+
+	if rule.OptionalOldSelf != nil {
+		result.OptionalOldSelf = *rule.OptionalOldSelf
+	}
+
+	return result
+}
@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"testing"
+
+	"github.com/tufin/oasdiff/checker"
+)
+
+func TestValidationRuleChangeLevel(t *testing.T) {
+	base := ValidationRule{
+		Rule:      "self.replicas >= oldSelf.replicas",
+		Message:   "replicas must not decrease",
+		Reason:    "FieldValueInvalid",
+		FieldPath: ".replicas",
+	}
+
+	testcases := []struct {
+		name     string
+		modify   func(r *ValidationRule)
+		expected checker.Level
+	}{
+		{
+			name:     "message changed",
+			modify:   func(r *ValidationRule) { r.Message = "replicas cannot be decreased" },
+			expected: 0,
+		},
+		{
+			name:     "message expression and reason changed",
+			modify:   func(r *ValidationRule) { r.MessageExpression = "'too few replicas'"; r.Reason = "FieldValueForbidden" },
+			expected: 0,
+		},
+		{
+			name:     "rule changed",
+			modify:   func(r *ValidationRule) { r.Rule = "self.replicas > oldSelf.replicas" },
+			expected: checker.WARN,
+		},
+		{
+			name:     "oldSelf made optional",
+			modify:   func(r *ValidationRule) { r.OptionalOldSelf = true },
+			expected: checker.WARN,
+		},
+		{
+			name:     "field path changed",
+			modify:   func(r *ValidationRule) { r.FieldPath = ".spec.replicas" },
+			expected: checker.INFO,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			revision := base
+			tc.modify(&revision)
+
+			if level := validationRuleChangeLevel(ValidationRuleChange{From: base, To: revision}); level != tc.expected {
+				t.Errorf("Expected level %v, but got %v.", tc.expected, level)
+			}
+		})
+	}
+
+	// making oldSelf required again only relaxes the rule
+	optional := base
+	optional.OptionalOldSelf = true
+
+	if level := validationRuleChangeLevel(ValidationRuleChange{From: optional, To: base}); level != 0 {
+		t.Errorf("Expected requiring oldSelf to not be breaking, but got level %v.", level)
+	}
+}
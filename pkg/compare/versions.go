@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	"fmt"
+
+	"github.com/tufin/oasdiff/checker"
+
+	"go.xrstf.de/crdiff/pkg/crd"
+)
+
+// compareVersionMetadata compares the served, deprecated and deprecationWarning
+// flags of a single version that exists in both base and revision.
+func compareVersionMetadata(base, revision *crd.Version) []Change {
+	changes := []Change{}
+
+	if base == nil || revision == nil {
+		return changes
+	}
+
+	if base.Served != revision.Served {
+		if revision.Served {
+			changes = append(changes, Change{
+				Description: "version is now served",
+			})
+		} else {
+			changes = append(changes, Change{
+				Breaking:    true,
+				Level:       checker.ERR,
+				Description: "version is no longer served",
+			})
+		}
+	}
+
+	if base.Deprecated != revision.Deprecated {
+		if revision.Deprecated {
+			description := "version has been deprecated"
+			if w := revision.DeprecationWarning; w != nil {
+				description = fmt.Sprintf("%s with warning %q", description, *w)
+			}
+
+			changes = append(changes, Change{
+				Description: description,
+			})
+		} else {
+			changes = append(changes, Change{
+				Description: "version is no longer deprecated",
+			})
+		}
+	} else if revision.Deprecated && stringValue(base.DeprecationWarning) != stringValue(revision.DeprecationWarning) {
+		changes = append(changes, Change{
+			Description: fmt.Sprintf("changed deprecation warning from %q to %q", stringValue(base.DeprecationWarning), stringValue(revision.DeprecationWarning)),
+		})
+	}
+
+	return changes
+}
+
+// compareStorageVersions reports if the storage version has moved. This is not
+// breaking by itself, but requires existing objects to be migrated before the
+// old version can be removed from the CRD.
+func compareStorageVersions(base, revision crd.CRD, baseVersions, revisionVersions []string) []Change {
+	baseStorage := storageVersion(base, baseVersions)
+	revisionStorage := storageVersion(revision, revisionVersions)
+
+	if baseStorage == "" || revisionStorage == "" || baseStorage == revisionStorage {
+		return nil
+	}
+
+	return []Change{{
+		Breaking: true,
+		Level:    checker.WARN,
+		Description: fmt.Sprintf(
+			"changed storage version from %q to %q; existing objects remain stored as %s until they are migrated and %s must not be removed before that",
+			baseStorage, revisionStorage, baseStorage, baseStorage,
+		),
+	}}
+}
+
+func storageVersion(c crd.CRD, versions []string) string {
+	for _, version := range versions {
+		if v := c.Version(version); v != nil && v.Storage {
+			return version
+		}
+	}
+
+	return ""
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
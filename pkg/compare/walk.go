@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package compare
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// schemaVisitor is called for every path that exists in both schemas.
+type schemaVisitor func(path string, base, revision *apiextensionsv1.JSONSchemaProps)
+
+// walkSchemas walks two schemas in parallel and calls the visitor for every
+// path that exists in both of them. Paths use the same syntax as the schema
+// changes reported by oasdiff (e.g. ".spec.containers.[].name").
+func walkSchemas(base, revision *apiextensionsv1.JSONSchemaProps, path string, visitor schemaVisitor) {
+	if base == nil || revision == nil {
+		return
+	}
+
+	visitor(rootPath(path), base, revision)
+
+	// ensure a stable order when visiting
+	for _, name := range sets.List(sets.KeySet(base.Properties)) {
+		revisionProp, exists := revision.Properties[name]
+		if !exists {
+			continue
+		}
+
+		baseProp := base.Properties[name]
+		walkSchemas(&baseProp, &revisionProp, path+"."+name, visitor)
+	}
+
+	if base.Items != nil && revision.Items != nil {
+		walkSchemas(base.Items.Schema, revision.Items.Schema, path+".[]", visitor)
+	}
+
+	if base.AdditionalProperties != nil && revision.AdditionalProperties != nil {
+		walkSchemas(base.AdditionalProperties.Schema, revision.AdditionalProperties.Schema, path+".*", visitor)
+	}
+}
+
+// updateSchemaDiff applies a change to the schema diff at the given path,
+// creating the diff if necessary.
+func updateSchemaDiff(result *CRDVersionDiff, path string, update func(d *CRDSchemaDiff)) {
+	schemaDiff := result.SchemaChanges[path] // rely on Go's runtime defaulting for non-existing keys
+	update(&schemaDiff)
+
+	result.SchemaChanges[path] = schemaDiff
+}
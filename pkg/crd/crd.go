@@ -9,5 +9,22 @@ type CRD interface {
 	Identifier() string
 	Versions() ([]string, error)
 	Scope() string
+	Names() apiextensionsv1.CustomResourceDefinitionNames
+	Conversion() *apiextensionsv1.CustomResourceConversion
+	PreserveUnknownFields() bool
+	Version(version string) *Version
 	Schema(version string) *apiextensionsv1.JSONSchemaProps
+	PrinterColumns(version string) []apiextensionsv1.CustomResourceColumnDefinition
+	Subresources(version string) *apiextensionsv1.CustomResourceSubresources
+	// Source returns where the CRD was loaded from, or nil if unknown.
+	Source() *Source
+}
+
+// Version contains the metadata for a single version of a CRD.
+type Version struct {
+	Name               string
+	Served             bool
+	Storage            bool
+	Deprecated         bool
+	DeprecationWarning *string
 }
@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package crd
+
+import (
+	"strings"
+)
+
+// Source describes where a CRD was loaded from.
+type Source struct {
+	// Filename is the file the CRD was loaded from. For git sources,
+	// it is relative to the repository root.
+	Filename string
+	// Document is the 1-based index of the YAML document within the file.
+	Document int
+	// Line is the line where the CRD's document starts.
+	Line int
+	// SchemaLines maps version names to the lines of their schema
+	// nodes, keyed by schema path (e.g. ".spec.ports.[]"). The empty
+	// path is the line of the version itself.
+	SchemaLines map[string]map[string]int
+}
+
+// SchemaLine returns the line of the schema node at the given path in the
+// given version. If the path does not exist (for example because the
+// property has been removed), the line of the closest existing parent is
+// returned instead. Paths can use both "[]" and oasdiff's "items" for array
+// items.
+func (s *Source) SchemaLine(version string, path string) int {
+	if s == nil {
+		return 0
+	}
+
+	lines := s.SchemaLines[version]
+	if line, exists := lines[path]; exists {
+		return line
+	}
+
+	line, exists := lines[""]
+	if !exists {
+		line = s.Line
+	}
+
+	current := "."
+	if l, exists := lines[current]; exists {
+		line = l
+	}
+
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		if segment == "" {
+			continue
+		}
+
+		next := ChildPath(current, segment)
+		if _, exists := lines[next]; !exists && segment == "items" {
+			next = ChildPath(current, "[]")
+		}
+
+		l, exists := lines[next]
+		if !exists {
+			break
+		}
+
+		current = next
+		line = l
+	}
+
+	return line
+}
+
+// ChildPath returns the path of the property (or "[]" for list items)
+// called name below the schema node at path. The root node can be given
+// as either "." or "".
+func ChildPath(path string, name string) string {
+	if path == "." {
+		return "." + name
+	}
+
+	return path + "." + name
+}
@@ -11,11 +11,29 @@ import (
 )
 
 type v1 struct {
-	crd apiextensionsv1.CustomResourceDefinition
+	crd    apiextensionsv1.CustomResourceDefinition
+	source *Source
 }
 
 func NewV1(crd apiextensionsv1.CustomResourceDefinition) CRD {
-	return &v1{crd}
+	return &v1{crd: crd}
+}
+
+// WithSource returns a copy of the CRD that remembers where it was loaded from.
+func WithSource(c CRD, source *Source) CRD {
+	switch typed := c.(type) {
+	case *v1:
+		copied := *typed
+		copied.source = source
+
+		return &copied
+	default:
+		return c
+	}
+}
+
+func (c *v1) Source() *Source {
+	return c.source
 }
 
 func (c *v1) Identifier() string {
@@ -26,6 +44,18 @@ func (c *v1) Scope() string {
 	return string(c.crd.Spec.Scope)
 }
 
+func (c *v1) Names() apiextensionsv1.CustomResourceDefinitionNames {
+	return c.crd.Spec.Names
+}
+
+func (c *v1) Conversion() *apiextensionsv1.CustomResourceConversion {
+	return c.crd.Spec.Conversion
+}
+
+func (c *v1) PreserveUnknownFields() bool {
+	return c.crd.Spec.PreserveUnknownFields
+}
+
 func (c *v1) Versions() ([]string, error) {
 	versions := sets.New[string]()
 	for _, v := range c.crd.Spec.Versions {
@@ -38,12 +68,52 @@ func (c *v1) Versions() ([]string, error) {
 	return sets.List(versions), nil
 }
 
+func (c *v1) Version(version string) *Version {
+	for _, v := range c.crd.Spec.Versions {
+		if v.Name == version {
+			return &Version{
+				Name:               v.Name,
+				Served:             v.Served,
+				Storage:            v.Storage,
+				Deprecated:         v.Deprecated,
+				DeprecationWarning: v.DeprecationWarning,
+			}
+		}
+	}
+
+	return nil
+}
+
 func (c *v1) Schema(version string) *apiextensionsv1.JSONSchemaProps {
 	for _, v := range c.crd.Spec.Versions {
 		if v.Name == version {
+			if v.Schema == nil {
+				return nil
+			}
+
 			return v.Schema.OpenAPIV3Schema
 		}
 	}
 
 	return nil
 }
+
+func (c *v1) PrinterColumns(version string) []apiextensionsv1.CustomResourceColumnDefinition {
+	for _, v := range c.crd.Spec.Versions {
+		if v.Name == version {
+			return v.AdditionalPrinterColumns
+		}
+	}
+
+	return nil
+}
+
+func (c *v1) Subresources(version string) *apiextensionsv1.CustomResourceSubresources {
+	for _, v := range c.crd.Spec.Versions {
+		if v.Name == version {
+			return v.Subresources
+		}
+	}
+
+	return nil
+}
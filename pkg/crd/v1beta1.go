@@ -4,62 +4,47 @@
 package crd
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/install"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
-	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
-type v1beta1 struct {
-	crd apiextensionsv1beta1.CustomResourceDefinition
-}
-
-func NewV1beta1(crd apiextensionsv1beta1.CustomResourceDefinition) CRD {
-	return &v1beta1{crd}
-}
+var scheme = runtime.NewScheme()
 
-func (c *v1beta1) Identifier() string {
-	return fmt.Sprintf("%s/%s", c.crd.Spec.Group, c.crd.Spec.Names.Kind)
+func init() {
+	install.Install(scheme)
 }
 
-func (c *v1beta1) Scope() string {
-	return string(c.crd.Spec.Scope)
-}
-
-func (c *v1beta1) Versions() ([]string, error) {
-	versions := sets.New[string]()
-	for _, v := range c.crd.Spec.Versions {
-		if versions.Has(v.Name) {
-			return nil, fmt.Errorf("defines version %q multiple times", v.Name)
-		}
-		versions.Insert(v.Name)
+// NewV1beta1 converts the given v1beta1 CRD into a v1 CRD, exactly like the
+// apiserver would do it: Defaults are applied (e.g. the deprecated
+// spec.version is turned into spec.versions) and the top-level validation,
+// subresources and additionalPrinterColumns are moved into each version.
+// This makes it possible to compare legacy CRDs with their v1 successors.
+func NewV1beta1(crd apiextensionsv1beta1.CustomResourceDefinition) (CRD, error) {
+	converted, err := convertV1beta1(crd.DeepCopy())
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert to apiextensions/v1: %w", err)
 	}
 
-	return sets.List(versions), nil
+	return NewV1(*converted), nil
 }
 
-// Schema converts the v1beta1 schema to v1, as those types are thankfully
-// identical and it makes diffing easier if all CRDs use the same type for
-// their schemas.
-func (c *v1beta1) Schema(version string) *apiextensionsv1.JSONSchemaProps {
-	for _, v := range c.crd.Spec.Versions {
-		if v.Name == version {
-			var buf bytes.Buffer
-			if json.NewEncoder(&buf).Encode(v.Schema.OpenAPIV3Schema) != nil {
-				return nil
-			}
+func convertV1beta1(crd *apiextensionsv1beta1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+	scheme.Default(crd)
 
-			var v1 apiextensionsv1.JSONSchemaProps
-			if json.NewDecoder(&buf).Decode(&v1) != nil {
-				return nil
-			}
+	internal := &apiextensions.CustomResourceDefinition{}
+	if err := scheme.Convert(crd, internal, nil); err != nil {
+		return nil, err
+	}
 
-			return &v1
-		}
+	converted := &apiextensionsv1.CustomResourceDefinition{}
+	if err := scheme.Convert(internal, converted, nil); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return converted, nil
 }
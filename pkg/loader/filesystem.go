@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package loader
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// filesystem abstracts the local disk and git trees, so that both can
+// share the same directory walking and file filtering logic.
+type filesystem interface {
+	IsDir(path string) (bool, error)
+	ReadDir(path string) ([]fs.DirEntry, error)
+	Open(path string) (io.ReadCloser, error)
+	Join(elem ...string) string
+}
+
+type localFilesystem struct{}
+
+var _ filesystem = localFilesystem{}
+
+func (localFilesystem) IsDir(path string) (bool, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	return stat.IsDir(), nil
+}
+
+func (localFilesystem) ReadDir(path string) ([]fs.DirEntry, error) {
+	return os.ReadDir(path)
+}
+
+func (localFilesystem) Open(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
+
+func (localFilesystem) Join(elem ...string) string {
+	return filepath.Join(elem...)
+}
@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package loader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+const (
+	// GitSourcePrefix marks a source as living inside a git repository,
+	// e.g. "git:origin/main:crds/". The path is always relative to the
+	// repository root.
+	GitSourcePrefix = "git:"
+)
+
+// IsGitSource returns true if the given source uses the git:<ref>:<path> syntax.
+func IsGitSource(source string) bool {
+	return strings.HasPrefix(source, GitSourcePrefix)
+}
+
+// parseGitSource splits a "git:<ref>:<path>" source into its ref and path.
+// If no path is given, the repository root is used.
+func parseGitSource(source string) (ref string, filename string, err error) {
+	ref, filename, _ = strings.Cut(strings.TrimPrefix(source, GitSourcePrefix), ":")
+	if ref == "" {
+		return "", "", fmt.Errorf("no git ref given in %q, expected git:<ref>:<path>", source)
+	}
+
+	// refs are passed as arguments to git and must not be mistaken for options
+	if strings.HasPrefix(ref, "-") {
+		return "", "", fmt.Errorf("invalid git ref %q in %q, refs must not start with a dash", ref, source)
+	}
+
+	filename = path.Clean("/" + filename)
+	filename = strings.TrimPrefix(filename, "/")
+
+	return ref, filename, nil
+}
+
+// gitFilesystem reads files and directories directly from the object
+// database of a local git repository, without requiring a checkout.
+type gitFilesystem struct {
+	repository string
+	ref        string
+}
+
+var _ filesystem = &gitFilesystem{}
+
+func newGitFilesystem(repository string, ref string) (*gitFilesystem, error) {
+	gfs := &gitFilesystem{
+		repository: repository,
+		ref:        ref,
+	}
+
+	// make sure the ref exists, so users get a helpful error message early
+	if _, err := gfs.git("rev-parse", "--verify", "--quiet", ref+"^{tree}"); err != nil {
+		return nil, fmt.Errorf("unknown git ref %q: %w", ref, err)
+	}
+
+	return gfs, nil
+}
+
+func (g *gitFilesystem) object(filename string) string {
+	// "<ref>:" is the root tree of the given ref
+	return fmt.Sprintf("%s:%s", g.ref, filename)
+}
+
+func (g *gitFilesystem) IsDir(filename string) (bool, error) {
+	output, err := g.git("cat-file", "-t", g.object(filename))
+	if err != nil {
+		return false, fmt.Errorf("%s does not exist in %s: %w", filename, g.ref, fs.ErrNotExist)
+	}
+
+	return strings.TrimSpace(string(output)) == "tree", nil
+}
+
+func (g *gitFilesystem) ReadDir(filename string) ([]fs.DirEntry, error) {
+	output, err := g.git("ls-tree", "-z", g.object(filename))
+	if err != nil {
+		return nil, err
+	}
+
+	result := []fs.DirEntry{}
+
+	for _, line := range bytes.Split(output, []byte{0}) {
+		if len(line) == 0 {
+			continue
+		}
+
+		// <mode> SP <type> SP <object> TAB <file>
+		meta, name, found := strings.Cut(string(line), "\t")
+		if !found {
+			return nil, fmt.Errorf("unexpected ls-tree output %q", string(line))
+		}
+
+		fields := strings.Fields(meta)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected ls-tree output %q", string(line))
+		}
+
+		// ignore submodules, they are not part of this repository
+		if fields[1] == "commit" {
+			continue
+		}
+
+		result = append(result, gitDirEntry{
+			name:  name,
+			isDir: fields[1] == "tree",
+		})
+	}
+
+	return result, nil
+}
+
+func (g *gitFilesystem) Open(filename string) (io.ReadCloser, error) {
+	output, err := g.git("cat-file", "blob", g.object(filename))
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(output)), nil
+}
+
+func (g *gitFilesystem) Join(elem ...string) string {
+	return path.Join(elem...)
+}
+
+func (g *gitFilesystem) git(args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.repository
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return nil, errors.New(msg)
+			}
+		}
+
+		return nil, fmt.Errorf("failed to run git %s: %w", strings.Join(args, " "), err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+type gitDirEntry struct {
+	name  string
+	isDir bool
+}
+
+var _ fs.DirEntry = gitDirEntry{}
+
+func (e gitDirEntry) Name() string {
+	return e.name
+}
+
+func (e gitDirEntry) IsDir() bool {
+	return e.isDir
+}
+
+func (e gitDirEntry) Type() fs.FileMode {
+	if e.isDir {
+		return fs.ModeDir
+	}
+
+	return 0
+}
+
+func (e gitDirEntry) Info() (fs.FileInfo, error) {
+	return nil, errors.New("file info is not available for git objects")
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package loader
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestParseGitSource(t *testing.T) {
+	testcases := []struct {
+		source   string
+		ref      string
+		filename string
+		invalid  bool
+	}{
+		{source: "git:main", ref: "main", filename: ""},
+		{source: "git:main:", ref: "main", filename: ""},
+		{source: "git:main:.", ref: "main", filename: ""},
+		{source: "git:origin/main:crds/", ref: "origin/main", filename: "crds"},
+		{source: "git:v1.2.3:./crds/../deploy/crd.yaml", ref: "v1.2.3", filename: "deploy/crd.yaml"},
+		{source: "git::crds", invalid: true},
+		{source: "git:--output=/tmp/foo:crds", invalid: true},
+		{source: "git:-h", invalid: true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.source, func(t *testing.T) {
+			ref, filename, err := parseGitSource(tc.source)
+			if tc.invalid {
+				if err == nil {
+					t.Fatal("Expected error, but got none.")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if ref != tc.ref {
+				t.Errorf("Expected ref %q, got %q.", tc.ref, ref)
+			}
+
+			if filename != tc.filename {
+				t.Errorf("Expected filename %q, got %q.", tc.filename, filename)
+			}
+		})
+	}
+}
+
+const testCRD = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: things.api.group
+spec:
+  group: api.group
+  names:
+    kind: Thing
+    plural: things
+  scope: Cluster
+  versions:
+    - name: v1
+      schema:
+        openAPIV3Schema:
+          type: object
+`
+
+func TestLoadCRDsFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+
+	runGit := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		// do not depend on the developer's git configuration (e.g. commit signing)
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL="+os.DevNull, "GIT_CONFIG_NOSYSTEM=1")
+
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+
+	writeFile := func(filename string, content string) {
+		t.Helper()
+
+		fullPath := filepath.Join(repo, filename)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	runGit("init", "--quiet")
+	runGit("config", "user.name", "test")
+	runGit("config", "user.email", "test@example.com")
+	writeFile("crds/nested/thing.yaml", testCRD)
+	writeFile("crds/README.md", "not a CRD")
+	runGit("add", ".")
+	runGit("commit", "--quiet", "-m", "initial")
+
+	// remove the file from the working tree to ensure it's read from git
+	if err := os.RemoveAll(filepath.Join(repo, "crds")); err != nil {
+		t.Fatalf("Failed to remove working tree files: %v", err)
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	opt := NewDefaultOptions()
+	opt.GitRepository = repo
+
+	for _, source := range []string{"git:HEAD", "git:HEAD:crds", "git:HEAD:crds/nested/thing.yaml"} {
+		crds, err := LoadCRDs(source, opt, log)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", source, err)
+		}
+
+		if _, exists := crds["api.group/Thing"]; !exists || len(crds) != 1 {
+			t.Fatalf("Expected to find exactly api.group/Thing in %s, but got %v.", source, crds)
+		}
+	}
+
+	if _, err := LoadCRDs("git:HEAD:missing", opt, log); err == nil {
+		t.Fatal("Expected error when loading non-existing path, but got none.")
+	}
+
+	if _, err := LoadCRDs("git:does-not-exist:crds", opt, log); err == nil {
+		t.Fatal("Expected error when loading non-existing ref, but got none.")
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -24,6 +23,10 @@ import (
 
 type Options struct {
 	FileExtensions []string
+
+	// GitRepository is the directory of the git repository that is used for
+	// git:<ref>:<path> sources. If empty, the current working directory is used.
+	GitRepository string
 }
 
 func NewDefaultOptions() *Options {
@@ -37,21 +40,40 @@ func LoadCRDs(source string, opt *Options, log logrus.FieldLogger) (map[string]c
 		opt = NewDefaultOptions()
 	}
 
-	stat, err := os.Stat(source)
+	var fsys filesystem = localFilesystem{}
+
+	if IsGitSource(source) {
+		ref, filename, err := parseGitSource(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source: %w", err)
+		}
+
+		fsys, err = newGitFilesystem(opt.GitRepository, ref)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source: %w", err)
+		}
+
+		log = log.WithField("ref", ref)
+		source = filename
+	}
+
+	isDir, err := fsys.IsDir(source)
 	if err != nil {
 		return nil, fmt.Errorf("invalid source: %w", err)
 	}
 
-	if stat.IsDir() {
-		absSource, err := filepath.Abs(source)
-		if err != nil {
-			return nil, fmt.Errorf("failed to determine absolute path: %w", err)
+	if isDir {
+		if _, ok := fsys.(localFilesystem); ok {
+			source, err = filepath.Abs(source)
+			if err != nil {
+				return nil, fmt.Errorf("failed to determine absolute path: %w", err)
+			}
 		}
 
-		return forbidDuplicates(loadCRDsFromDirectory(absSource, opt, log))
+		return forbidDuplicates(loadCRDsFromDirectory(fsys, source, opt, log))
 	}
 
-	return forbidDuplicates(loadCRDsFromFile(source, true, opt, log))
+	return forbidDuplicates(loadCRDsFromFile(fsys, source, true, opt, log))
 }
 
 func forbidDuplicates(allCRDs []crd.CRD, err error) (map[string]crd.CRD, error) {
@@ -81,7 +103,7 @@ const (
 	bufSize = 5 * 1024 * 1024
 )
 
-func loadCRDsFromFile(source string, logFullFilename bool, opt *Options, log logrus.FieldLogger) ([]crd.CRD, error) {
+func loadCRDsFromFile(fsys filesystem, source string, logFullFilename bool, opt *Options, log logrus.FieldLogger) ([]crd.CRD, error) {
 	if logFullFilename {
 		log = log.WithField("filename", source)
 	} else {
@@ -89,13 +111,25 @@ func loadCRDsFromFile(source string, logFullFilename bool, opt *Options, log log
 	}
 	log.Debug("Reading file…")
 
-	f, err := os.Open(source)
+	f, err := fsys.Open(source)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer f.Close()
 
-	docSplitter := yamlutil.NewDocumentDecoder(f)
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// source locations are only informational, so failing to determine
+	// them must not prevent comparing the CRDs
+	sources, err := parseSources(source, content)
+	if err != nil {
+		log.WithError(err).Debug("Failed to determine source locations.")
+	}
+
+	docSplitter := yamlutil.NewDocumentDecoder(io.NopCloser(bytes.NewReader(content)))
 	defer docSplitter.Close()
 
 	result := []crd.CRD{}
@@ -123,16 +157,22 @@ func loadCRDsFromFile(source string, logFullFilename bool, opt *Options, log log
 			continue
 		}
 
+		// CRDs are found in the same order by both parsers
+		if candidates := sources[crdObj.Identifier()]; len(candidates) > 0 {
+			crdObj = crd.WithSource(crdObj, candidates[0])
+			sources[crdObj.Identifier()] = candidates[1:]
+		}
+
 		result = append(result, crdObj)
 	}
 
 	return result, nil
 }
 
-func loadCRDsFromDirectory(rootDir string, opt *Options, log logrus.FieldLogger) ([]crd.CRD, error) {
+func loadCRDsFromDirectory(fsys filesystem, rootDir string, opt *Options, log logrus.FieldLogger) ([]crd.CRD, error) {
 	log = log.WithField("directory", rootDir)
 
-	contents, err := os.ReadDir(rootDir)
+	contents, err := fsys.ReadDir(rootDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read directory: %w", err)
 	}
@@ -140,16 +180,16 @@ func loadCRDsFromDirectory(rootDir string, opt *Options, log logrus.FieldLogger)
 	result := []crd.CRD{}
 
 	for _, entry := range contents {
-		fullPath := filepath.Join(rootDir, entry.Name())
+		fullPath := fsys.Join(rootDir, entry.Name())
 
 		if entry.IsDir() {
-			subresult, err := loadCRDsFromDirectory(fullPath, opt, log)
+			subresult, err := loadCRDsFromDirectory(fsys, fullPath, opt, log)
 			if err != nil {
 				return nil, fmt.Errorf("failed to read directory %s: %w", fullPath, err)
 			}
 			result = append(result, subresult...)
 		} else if hasExtension(entry.Name(), opt.FileExtensions) {
-			subresult, err := loadCRDsFromFile(fullPath, false, opt, log)
+			subresult, err := loadCRDsFromFile(fsys, fullPath, false, opt, log)
 			if err != nil {
 				return nil, fmt.Errorf("failed to read file %s: %w", fullPath, err)
 			}
@@ -202,7 +242,10 @@ func parseYAML(data []byte) (crd.CRD, error) {
 		if err := decoder.Decode(&crdInstance); err != nil {
 			return nil, fmt.Errorf("document is not valid apiextensions/v1beta1 CustomResourceDefinition: %w", err)
 		}
-		crdObj = crd.NewV1beta1(crdInstance)
+		crdObj, err = crd.NewV1beta1(crdInstance)
+		if err != nil {
+			return nil, fmt.Errorf("document is not valid apiextensions/v1beta1 CustomResourceDefinition: %w", err)
+		}
 
 	default:
 		return nil, fmt.Errorf("document is using unrecognized API version %q", candidate.GetAPIVersion())
@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package loader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+
+	"go.xrstf.de/crdiff/pkg/crd"
+)
+
+// parseSources determines the source location of all CRDs in a file, keyed
+// by their identifier. The Kubernetes YAML decoder does not retain any
+// positions, so the file is parsed a second time into yaml.v3 nodes.
+func parseSources(filename string, content []byte) (map[string][]*crd.Source, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	result := map[string][]*crd.Source{}
+
+	for document := 1; true; document++ {
+		var node yaml.Node
+		if err := decoder.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, fmt.Errorf("document %d is invalid: %w", document, err)
+		}
+
+		if len(node.Content) == 0 {
+			continue
+		}
+
+		root := resolve(node.Content[0])
+		if stringValue(lookup(root, "kind")) != "CustomResourceDefinition" {
+			continue
+		}
+
+		spec := lookup(root, "spec")
+		identifier := fmt.Sprintf("%s/%s", stringValue(lookup(spec, "group")), stringValue(lookup(lookup(spec, "names"), "kind")))
+
+		source := &crd.Source{
+			Filename:    filename,
+			Document:    document,
+			Line:        root.Line,
+			SchemaLines: map[string]map[string]int{},
+		}
+
+		collectSchemaLines(source, spec)
+
+		result[identifier] = append(result[identifier], source)
+	}
+
+	return result, nil
+}
+
+func collectSchemaLines(source *crd.Source, spec *yaml.Node) {
+	versions := lookup(spec, "versions")
+
+	versionNames := []string{}
+	if versions != nil {
+		for _, version := range versions.Content {
+			version = resolve(version)
+			versionNames = append(versionNames, stringValue(lookup(version, "name")))
+		}
+	} else if name := stringValue(lookup(spec, "version")); name != "" {
+		// apiextensions/v1beta1 allowed to only specify a single version
+		versionNames = append(versionNames, name)
+	}
+
+	// apiextensions/v1beta1 can define a schema for all versions
+	if key, schema := lookupWithKey(lookup(spec, "validation"), "openAPIV3Schema"); schema != nil {
+		for _, name := range versionNames {
+			walkSchemaNode(source.SchemaLines, name, ".", key.Line, schema)
+		}
+	}
+
+	if versions == nil {
+		return
+	}
+
+	for i, version := range versions.Content {
+		version = resolve(version)
+		name := versionNames[i]
+
+		lines, exists := source.SchemaLines[name]
+		if !exists {
+			lines = map[string]int{}
+			source.SchemaLines[name] = lines
+		}
+
+		lines[""] = version.Line
+
+		if key, schema := lookupWithKey(lookup(version, "schema"), "openAPIV3Schema"); schema != nil {
+			walkSchemaNode(source.SchemaLines, name, ".", key.Line, schema)
+		}
+	}
+}
+
+// walkSchemaNode records the line for every schema node, using the line of
+// the key that defines the node, so that findings point to the property name.
+func walkSchemaNode(allLines map[string]map[string]int, version string, path string, line int, schema *yaml.Node) {
+	lines, exists := allLines[version]
+	if !exists {
+		lines = map[string]int{}
+		allLines[version] = lines
+	}
+
+	lines[path] = line
+
+	if properties := lookup(schema, "properties"); properties != nil {
+		for i := 0; i+1 < len(properties.Content); i += 2 {
+			key := properties.Content[i]
+			walkSchemaNode(allLines, version, crd.ChildPath(path, key.Value), key.Line, resolve(properties.Content[i+1]))
+		}
+	}
+
+	if key, items := lookupWithKey(schema, "items"); items != nil && items.Kind == yaml.MappingNode {
+		walkSchemaNode(allLines, version, crd.ChildPath(path, "[]"), key.Line, items)
+	}
+
+	if key, additional := lookupWithKey(schema, "additionalProperties"); additional != nil && additional.Kind == yaml.MappingNode {
+		walkSchemaNode(allLines, version, crd.ChildPath(path, "*"), key.Line, additional)
+	}
+}
+
+func lookup(node *yaml.Node, key string) *yaml.Node {
+	_, value := lookupWithKey(node, key)
+	return value
+}
+
+func lookupWithKey(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], resolve(node.Content[i+1])
+		}
+	}
+
+	return nil, nil
+}
+
+func resolve(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	return node
+}
+
+func stringValue(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+
+	return node.Value
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2023 Christoph Mewes
+// SPDX-License-Identifier: MIT
+
+package loader
+
+import (
+	"testing"
+)
+
+const multiDocumentCRDs = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unrelated
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: things.api.group
+spec:
+  group: api.group
+  names:
+    kind: Thing
+  versions:
+    - name: v1
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              properties:
+                ports:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      name:
+                        type: string
+`
+
+func TestParseSources(t *testing.T) {
+	sources, err := parseSources("crds.yaml", []byte(multiDocumentCRDs))
+	if err != nil {
+		t.Fatalf("Failed to parse sources: %v", err)
+	}
+
+	candidates := sources["api.group/Thing"]
+	if len(candidates) != 1 {
+		t.Fatalf("Expected 1 source, got %d.", len(candidates))
+	}
+
+	source := candidates[0]
+	if source.Filename != "crds.yaml" || source.Document != 2 || source.Line != 6 {
+		t.Errorf("Expected crds.yaml, document 2, line 6, got %s, document %d, line %d.", source.Filename, source.Document, source.Line)
+	}
+
+	testcases := []struct {
+		path string
+		line int
+	}{
+		{path: "", line: 15},
+		{path: ".", line: 17},
+		{path: ".spec.ports.[].name", line: 28},
+		// oasdiff uses "items" instead of "[]"
+		{path: ".spec.ports.items.name", line: 28},
+		// removed properties point to their closest parent
+		{path: ".spec.ports.[].protocol", line: 25},
+	}
+
+	for _, tc := range testcases {
+		if line := source.SchemaLine("v1", tc.path); line != tc.line {
+			t.Errorf("Expected %q to be on line %d, got %d.", tc.path, tc.line, line)
+		}
+	}
+}